@@ -2,9 +2,12 @@ package promptui
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"text/template"
+
+	"github.com/logrhythm/promptui/screenbuf"
 )
 
 const esc = "\033["
@@ -51,6 +54,45 @@ const (
 	BGWhite
 )
 
+// The possible bright background colors of text inside the application.
+//
+// These constants are called through the use of the Styler function.
+const (
+	BGBrightBlack attribute = iota + 100
+	BGBrightRed
+	BGBrightGreen
+	BGBrightYellow
+	BGBrightBlue
+	BGBrightMagenta
+	BGBrightCyan
+	BGBrightWhite
+)
+
+// The flags marking the attributes of the extended colors returned by RGB and Color256. The color itself is stored
+// in the lower bits of the attribute.
+const (
+	rgbColor attribute = 1 << (24 + iota)
+	paletteColor
+)
+
+// RGB returns the attribute of the 24-bit foreground color made of the given red, green and blue components. It can
+// be combined with the other constants in the Styler function.
+//
+// When the terminal does not advertise truecolor support through the COLORTERM environment variable, the nearest
+// basic color is used instead.
+func RGB(r, g, b uint8) attribute {
+	return rgbColor | attribute(r)<<16 | attribute(g)<<8 | attribute(b)
+}
+
+// Color256 returns the attribute of the foreground color n of the 256-color palette. It can be combined with the
+// other constants in the Styler function.
+//
+// When the terminal does not advertise 256-color support through the TERM or COLORTERM environment variables, the
+// nearest basic color is used instead.
+func Color256(n uint8) attribute {
+	return paletteColor | attribute(n)
+}
+
 // ResetCode is the character code used to reset the terminal formatting
 var ResetCode = fmt.Sprintf("%s%dm", esc, reset)
 
@@ -58,12 +100,17 @@ const (
 	hideCursor = esc + "?25l"
 	showCursor = esc + "?25h"
 	clearLine  = esc + "2K"
+	bell       = "\a"
 )
 
 // FuncMap defines template helpers for the output. It can be extended as a regular map.
 //
 // The functions inside the map link the state, color and background colors strings detected in templates to a Styler
-// function that applies the given style using the corresponding constant.
+// function that applies the given style using the corresponding constant. The rgb and color256 helpers take the
+// color before the text, as in {{ .Name | rgb 255 128 0 }}. The icon helpers, such as iconGood, render the icons
+// of the package or the IconSet of the prompt or select. The padRight and padLeft helpers pad a text with spaces
+// up to a number of columns, as in {{ padRight .Name 20 }}, and the trunc helper cuts it to a number of columns,
+// ending it with …, so columns can be aligned as in {{ padRight (trunc .Name 20) 20 }}.
 var FuncMap = template.FuncMap{
 	"black":     Styler(FGBlack),
 	"red":       Styler(FGRed),
@@ -81,10 +128,139 @@ var FuncMap = template.FuncMap{
 	"bgMagenta": Styler(BGMagenta),
 	"bgCyan":    Styler(BGCyan),
 	"bgWhite":   Styler(BGWhite),
+
+	"bgBrightBlack":   Styler(BGBrightBlack),
+	"bgBrightRed":     Styler(BGBrightRed),
+	"bgBrightGreen":   Styler(BGBrightGreen),
+	"bgBrightYellow":  Styler(BGBrightYellow),
+	"bgBrightBlue":    Styler(BGBrightBlue),
+	"bgBrightMagenta": Styler(BGBrightMagenta),
+	"bgBrightCyan":    Styler(BGBrightCyan),
+	"bgBrightWhite":   Styler(BGBrightWhite),
+
 	"bold":      Styler(FGBold),
 	"faint":     Styler(FGFaint),
 	"italic":    Styler(FGItalic),
 	"underline": Styler(FGUnderline),
+
+	"iconInitial": func() string { return IconInitial },
+	"iconGood":    func() string { return IconGood },
+	"iconWarn":    func() string { return IconWarn },
+	"iconBad":     func() string { return IconBad },
+	"iconSelect":  func() string { return IconSelect },
+
+	"padRight": padRight,
+	"padLeft":  padLeft,
+	"trunc":    trunc,
+
+	"rgb": func(r, g, b uint8, v interface{}) string {
+		return Styler(RGB(r, g, b))(v)
+	},
+	"color256": func(n uint8, v interface{}) string {
+		return Styler(Color256(n))(v)
+	},
+}
+
+// colorsDisabled is set when the styles must not be applied, see DisableColors.
+var colorsDisabled = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+
+// The color depths supported by the terminal, limiting the colors used by the extended color attributes.
+const (
+	basicColors = iota
+	palette256Colors
+	trueColors
+)
+
+// colorDepth is the color depth advertised by the terminal through the COLORTERM and TERM environment variables.
+var colorDepth = detectColorDepth(os.Getenv("COLORTERM"), os.Getenv("TERM"))
+
+func detectColorDepth(colorterm, term string) int {
+	switch {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return trueColors
+	case strings.Contains(term, "256color"):
+		return palette256Colors
+	}
+	return basicColors
+}
+
+// basicPalette holds the usual values of the 8 basic colors followed by their bright variants.
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// code returns the SGR parameters of the attribute at the given color depth.
+func (a attribute) code(depth int) string {
+	switch {
+	case a&rgbColor != 0:
+		r, g, b := uint8(a>>16), uint8(a>>8), uint8(a)
+		if depth < trueColors {
+			return nearestBasic(r, g, b)
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	case a&paletteColor != 0:
+		n := uint8(a)
+		if depth < palette256Colors {
+			if n < 16 {
+				return basicCode(int(n))
+			}
+			r, g, b := paletteRGB(n)
+			return nearestBasic(r, g, b)
+		}
+		return fmt.Sprintf("38;5;%d", n)
+	}
+	return strconv.Itoa(int(a))
+}
+
+// basicCode returns the foreground code of the color i of the basic palette.
+func basicCode(i int) string {
+	if i < 8 {
+		return strconv.Itoa(int(FGBlack) + i)
+	}
+	return strconv.Itoa(int(FGBlack) + 60 + i - 8)
+}
+
+// nearestBasic returns the foreground code of the basic color closest to the given components.
+func nearestBasic(r, g, b uint8) string {
+	best, bestDist := 0, -1
+	for i, c := range basicPalette {
+		dr, dg, db := int(r)-int(c[0]), int(g)-int(c[1]), int(b)-int(c[2])
+		dist := dr*dr + dg*dg + db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return basicCode(best)
+}
+
+// paletteRGB returns the components of the color n of the 256-color palette.
+func paletteRGB(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := basicPalette[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return levels[n/36], levels[n/6%6], levels[n%6]
+	}
+	gray := 8 + 10*(n-232)
+	return gray, gray, gray
+}
+
+func init() {
+	setIcons()
+}
+
+// DisableColors sets whether the styling functions, including the template helpers of FuncMap, return the text
+// without any style. By default, the styles are disabled when the NO_COLOR environment variable is set or the
+// TERM environment variable is "dumb". DisableColors overrides this detection in both ways.
+//
+// The icons are reset to their default value, styled or not, by DisableColors.
+func DisableColors(disabled bool) {
+	colorsDisabled = disabled
+	setIcons()
 }
 
 func upLine(n uint) string {
@@ -97,24 +273,77 @@ func movementCode(n uint, code rune) string {
 
 // Styler is a function that accepts multiple possible styling transforms from the state,
 // color and background colors constants and transforms them into a templated string
-// to apply those styles in the CLI.
+// to apply those styles in the CLI. The extended colors of RGB and Color256 fall back
+// to the nearest basic color when the terminal does not support them.
 //
 // The returned styling function accepts a string that will be extended with
-// the wrapping function's styling attributes.
+// the wrapping function's styling attributes. The string is returned as is
+// when the colors are disabled, see DisableColors. It is the same as Style.
 func Styler(attrs ...attribute) func(interface{}) string {
-	attrstrs := make([]string, len(attrs))
-	for i, v := range attrs {
-		attrstrs[i] = strconv.Itoa(int(v))
-	}
+	return Style(attrs...)
+}
 
-	seq := strings.Join(attrstrs, ";")
+// Style returns a styling function applying the given attributes like Styler. The escape code of the attributes
+// is computed once, so the function can be called for each item of a long list, as in the templates of FuncMap.
+// The values that are not strings, nil included, are formatted with fmt.Sprint.
+func Style(attrs ...attribute) func(interface{}) string {
+	depth := colorDepth
+	start := sgr(attrs, depth)
 
 	return func(v interface{}) string {
-		end := ""
 		s, ok := v.(string)
-		if !ok || !strings.HasSuffix(s, ResetCode) {
-			end = ResetCode
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		if colorsDisabled {
+			return s
 		}
-		return fmt.Sprintf("%s%sm%v%s", esc, seq, v, end)
+
+		// the color depth only changes in tests, but the code is computed again if it does.
+		code := start
+		if colorDepth != depth {
+			code = sgr(attrs, colorDepth)
+		}
+
+		if ok && strings.HasSuffix(s, ResetCode) {
+			return code + s
+		}
+		return code + s + ResetCode
+	}
+}
+
+// sgr returns the escape code applying the attributes at the given color depth.
+func sgr(attrs []attribute, depth int) string {
+	codes := make([]string, len(attrs))
+	for i, a := range attrs {
+		codes[i] = a.code(depth)
 	}
+	return esc + strings.Join(codes, ";") + "m"
+}
+
+// padRight pads the text of v with spaces up to the given number of columns. The columns are counted with the
+// display width of the runes, ignoring the ANSI escape codes, and the text is returned as is when it is already
+// wider.
+func padRight(v interface{}, width int) string {
+	s := fmt.Sprintf("%v", v)
+	if n := screenbuf.StringWidth(s); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// padLeft pads the text of v with spaces on its left up to the given number of columns, like padRight, to align it
+// on the right.
+func padLeft(v interface{}, width int) string {
+	s := fmt.Sprintf("%v", v)
+	if n := screenbuf.StringWidth(s); n < width {
+		s = strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// trunc cuts the text of v to the given number of columns, ending it with … when it is cut. The columns are counted
+// like padRight, and the ANSI escape codes are kept, so styled text can be cut.
+func trunc(v interface{}, width int) string {
+	return screenbuf.Truncate(fmt.Sprintf("%v", v), width, "…")
 }
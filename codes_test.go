@@ -1,6 +1,10 @@
 package promptui
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
 
 func TestStyler(t *testing.T) {
 	t.Run("renders a single code", func(t *testing.T) {
@@ -19,6 +23,22 @@ func TestStyler(t *testing.T) {
 		}
 	})
 
+	t.Run("combines foreground, background and state codes", func(t *testing.T) {
+		bar := Styler(FGBold, FGWhite, BGBlue)("hi")
+		expected := "\033[1;37;44mhi\033[0m"
+		if bar != expected {
+			t.Errorf("style did not match: %s != %s", bar, expected)
+		}
+	})
+
+	t.Run("renders bright background codes", func(t *testing.T) {
+		bar := Styler(FGBlack, BGBrightYellow)("hi")
+		expected := "\033[30;103mhi\033[0m"
+		if bar != expected {
+			t.Errorf("style did not match: %s != %s", bar, expected)
+		}
+	})
+
 	t.Run("should not repeat reset codes for nested styles", func(t *testing.T) {
 		red := Styler(FGRed)("hi")
 		boldRed := Styler(FGBold)(red)
@@ -28,3 +48,199 @@ func TestStyler(t *testing.T) {
 		}
 	})
 }
+
+func TestDisableColors(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+
+	DisableColors(true)
+	if red := Styler(FGRed)("hi"); red != "hi" {
+		t.Errorf("style was applied: %q", red)
+	}
+
+	if IconGood != "✔" && IconGood != "v" {
+		t.Errorf("icon was styled: %q", IconGood)
+	}
+
+	DisableColors(false)
+	if red := Styler(FGRed)("hi"); red != "\033[31mhi\033[0m" {
+		t.Errorf("style was not applied: %q", red)
+	}
+}
+
+func TestExtendedColors(t *testing.T) {
+	defer func(depth int) { colorDepth = depth }(colorDepth)
+
+	tcs := []struct {
+		name     string
+		depth    int
+		attrs    []attribute
+		expected string
+	}{
+		{"truecolor", trueColors, []attribute{RGB(255, 128, 0)}, "\033[38;2;255;128;0mhi\033[0m"},
+		{"truecolor with bold", trueColors, []attribute{FGBold, RGB(1, 2, 3), FGUnderline}, "\033[1;38;2;1;2;3;4mhi\033[0m"},
+		{"truecolor fallback", basicColors, []attribute{RGB(250, 10, 10)}, "\033[91mhi\033[0m"},
+		{"truecolor fallback with 256 colors", palette256Colors, []attribute{RGB(0, 0, 230)}, "\033[34mhi\033[0m"},
+		{"256 colors", palette256Colors, []attribute{Color256(208)}, "\033[38;5;208mhi\033[0m"},
+		{"256 colors fallback", basicColors, []attribute{Color256(46)}, "\033[92mhi\033[0m"},
+		{"256 colors fallback to basic color", basicColors, []attribute{Color256(1)}, "\033[31mhi\033[0m"},
+		{"256 colors fallback of gray", basicColors, []attribute{Color256(244)}, "\033[90mhi\033[0m"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			colorDepth = tc.depth
+			got := Styler(tc.attrs...)("hi")
+			if got != tc.expected {
+				t.Errorf("style did not match: %q != %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExtendedColorsTemplate(t *testing.T) {
+	defer func(depth int) { colorDepth = depth }(colorDepth)
+	colorDepth = trueColors
+
+	tpl, err := template.New("").Funcs(FuncMap).Parse(`{{ "hi" | rgb 1 2 3 }} {{ "hi" | color256 208 }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\033[38;2;1;2;3mhi\033[0m \033[38;5;208mhi\033[0m"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	tcs := []struct {
+		colorterm, term string
+		expected        int
+	}{
+		{"truecolor", "xterm", trueColors},
+		{"24bit", "", trueColors},
+		{"", "xterm-256color", palette256Colors},
+		{"", "xterm", basicColors},
+	}
+
+	for _, tc := range tcs {
+		if got := detectColorDepth(tc.colorterm, tc.term); got != tc.expected {
+			t.Errorf("Expected depth %d for %q and %q, got %d", tc.expected, tc.colorterm, tc.term, got)
+		}
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tcs := []struct {
+		name     string
+		text     interface{}
+		width    int
+		expected string
+	}{
+		{"pads ascii", "abc", 6, "abc   "},
+		{"counts wide runes", "日本", 6, "日本  "},
+		{"ignores escape codes", "\033[1mab\033[0m", 4, "\033[1mab\033[0m  "},
+		{"keeps wider text", "abcdef", 3, "abcdef"},
+		{"formats values", 42, 4, "42  "},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := padRight(tc.text, tc.width)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPadLeft(t *testing.T) {
+	tcs := []struct {
+		name     string
+		text     interface{}
+		width    int
+		expected string
+	}{
+		{"pads ascii", "abc", 6, "   abc"},
+		{"counts wide runes", "日本", 6, "  日本"},
+		{"keeps wider text", "abcdef", 3, "abcdef"},
+		{"formats values", 42, 4, "  42"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := padLeft(tc.text, tc.width)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTrunc(t *testing.T) {
+	tcs := []struct {
+		name     string
+		text     interface{}
+		width    int
+		expected string
+	}{
+		{"keeps narrower text", "abc", 6, "abc"},
+		{"cuts with an ellipsis", "abcdef", 4, "abc…"},
+		{"counts wide runes", "日本語", 5, "日本…"},
+		{"keeps escape codes", "\033[1mabcdef\033[0m", 3, "\033[1mab…\033[0m"},
+		{"formats values", 123456, 3, "12…"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got := trunc(tc.text, tc.width)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	tcs := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "string", value: "hi", expected: "\033[31mhi\033[0m"},
+		{name: "number", value: 42, expected: "\033[31m42\033[0m"},
+		{name: "nil", value: nil, expected: "\033[31m<nil>\033[0m"},
+		{name: "styled string", value: "\033[1mhi\033[0m", expected: "\033[31m\033[1mhi\033[0m"},
+	}
+
+	red := Style(FGRed)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := red(tc.value); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+// BenchmarkStyle renders the styled items of a long list, as a select does with the templates of FuncMap.
+func BenchmarkStyle(b *testing.B) {
+	tpl, err := template.New("").Funcs(FuncMap).Parse(`{{ "▸" | cyan }} {{ . | cyan | underline }}`)
+	if err != nil {
+		b.Fatalf("Unexpected error parsing template %v", err)
+	}
+
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := tpl.Execute(&buf, "Bell Pepper"); err != nil {
+			b.Fatalf("Unexpected error executing template %v", err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package promptui
 
-import "fmt"
+import (
+	"fmt"
+	"unicode"
+)
 
 // Pointer is A specific type that translates a given set of runes into a given
 // set of runes pointed at by the cursor.
@@ -47,6 +50,9 @@ type Cursor struct {
 	// Put the cursor before this slice
 	Position int
 	erase    bool
+	// scrub zeroes the runes the input no longer holds, for masked input. Listen then returns no line, since its
+	// copy of the input would not be zeroed.
+	scrub bool
 }
 
 // NewCursor create a new cursor, with the DefaultCursor, the specified input,
@@ -99,7 +105,13 @@ func format(a []rune, c *Cursor) string {
 	var b []rune
 
 	out := make([]rune, 0)
-	if i < len(a) {
+	if i < len(a) && a[i] == '\n' {
+		// keep the line break, the cursor is displayed at the end of the line
+		b = c.Cursor([]rune{})
+		out = append(out, a[:i]...)
+		out = append(out, b...)
+		out = append(out, a[i:]...)
+	} else if i < len(a) {
 		b = c.Cursor([]rune(a[i : i+1]))
 		out = append(out, a[:i]...)   // does not include i
 		out = append(out, b...)       // add the cursor
@@ -131,12 +143,17 @@ func (c *Cursor) FormatMask(mask rune) string {
 // Update inserts newinput into the input []rune in the appropriate place.
 // The cursor is moved to the end of the inputed sequence.
 func (c *Cursor) Update(newinput string) {
-	a := c.input
 	b := []rune(newinput)
 	i := c.Position
-	a = append(a[:i], append(b, a[i:]...)...)
-	c.input = a
+	a := make([]rune, 0, len(c.input)+len(b))
+	a = append(a, c.input[:i]...)
+	a = append(a, b...)
+	a = append(a, c.input[i:]...)
+	c.setInput(a)
 	c.Move(len(b))
+	if c.scrub {
+		zeroRunes(b)
+	}
 }
 
 // Get returns a copy of the input
@@ -147,10 +164,37 @@ func (c *Cursor) Get() string {
 // Replace replaces the previous input with whatever is specified, and moves the
 // cursor to the end position
 func (c *Cursor) Replace(input string) {
-	c.input = []rune(input)
+	c.setInput([]rune(input))
 	c.End()
 }
 
+// setInput replaces the input with a. When scrub is set, the runes of the previous input array not held by a are
+// zeroed.
+func (c *Cursor) setInput(a []rune) {
+	if c.scrub {
+		old := c.input[:cap(c.input)]
+		if cap(a) > 0 && cap(old) > 0 && &a[:cap(a)][0] == &old[0] {
+			old = old[len(a):]
+		}
+		zeroRunes(old)
+	}
+	c.input = a
+}
+
+// clear zeroes the input and empties it.
+func (c *Cursor) clear() {
+	zeroRunes(c.input[:cap(c.input)])
+	c.input = nil
+	c.Position = 0
+}
+
+// zeroRunes overwrites r with zeros.
+func zeroRunes(r []rune) {
+	for i := range r {
+		r[i] = 0
+	}
+}
+
 // Place moves the cursor to the absolute array index specified by position
 func (c *Cursor) Place(position int) {
 	c.Position = position
@@ -164,6 +208,55 @@ func (c *Cursor) Move(shift int) {
 	c.correctPosition()
 }
 
+// lineStart returns the index of the first rune of the line containing the index i in a multi-line input.
+func (c *Cursor) lineStart(i int) int {
+	for i > 0 && c.input[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+// lineEnd returns the index of the line break ending the line containing the index i in a multi-line input, or
+// the length of the input for the last line.
+func (c *Cursor) lineEnd(i int) int {
+	for i < len(c.input) && c.input[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+// LineUp moves the cursor to the same column of the previous line in a multi-line input, or to the end of the
+// previous line if it is shorter. If the cursor is already on the first line, nothing happens.
+func (c *Cursor) LineUp() {
+	start := c.lineStart(c.Position)
+	if start == 0 {
+		return
+	}
+
+	col := c.Position - start
+	prevStart := c.lineStart(start - 1)
+	if prevLen := start - 1 - prevStart; col > prevLen {
+		col = prevLen
+	}
+	c.Place(prevStart + col)
+}
+
+// LineDown moves the cursor to the same column of the next line in a multi-line input, or to the end of the
+// next line if it is shorter. If the cursor is already on the last line, nothing happens.
+func (c *Cursor) LineDown() {
+	end := c.lineEnd(c.Position)
+	if end == len(c.input) {
+		return
+	}
+
+	col := c.Position - c.lineStart(c.Position)
+	nextStart := end + 1
+	if nextLen := c.lineEnd(nextStart) - nextStart; col > nextLen {
+		col = nextLen
+	}
+	c.Place(nextStart + col)
+}
+
 // Backspace removes the rune that precedes the cursor
 //
 // It handles being at the beginning or end of the row, and moves the cursor to
@@ -176,14 +269,51 @@ func (c *Cursor) Backspace() {
 		return
 	}
 	if i == len(a) {
-		c.input = a[:i-1]
+		c.setInput(a[:i-1])
 	} else {
-		c.input = append(a[:i-1], a[i:]...)
+		c.setInput(append(a[:i-1], a[i:]...))
 	}
 	// now it's pointing to the i+1th element
 	c.Move(-1)
 }
 
+// Delete removes the rune under the cursor, like <Delete> in most editors. The cursor stays in place, and nothing
+// is removed at the end of the input.
+func (c *Cursor) Delete() {
+	if c.Position >= len(c.input) {
+		return
+	}
+	c.setInput(append(c.input[:c.Position], c.input[c.Position+1:]...))
+}
+
+// DeleteWord removes the word preceding the cursor, like <Ctrl+W> in most shells. The spaces right before the
+// cursor are removed first, then the runes up to the previous space or the beginning of the input.
+func (c *Cursor) DeleteWord() {
+	i := c.Position
+	for i > 0 && unicode.IsSpace(c.input[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(c.input[i-1]) {
+		i--
+	}
+	c.setInput(append(c.input[:i], c.input[c.Position:]...))
+	c.Place(i)
+}
+
+// KillToEnd removes the runes from the cursor to the end of the line, like <Ctrl+K> in most shells.
+func (c *Cursor) KillToEnd() {
+	end := c.lineEnd(c.Position)
+	c.setInput(append(c.input[:c.Position], c.input[end:]...))
+}
+
+// KillToStart removes the runes from the start of the line to the cursor and moves the cursor to the start of
+// the line, like <Ctrl+U> in most shells.
+func (c *Cursor) KillToStart() {
+	start := c.lineStart(c.Position)
+	c.setInput(append(c.input[:start], c.input[c.Position:]...))
+	c.Place(start)
+}
+
 // Listen is a readline Listener that updates internal cursor state appropriately.
 func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
 	if line != nil {
@@ -194,13 +324,35 @@ func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
 	switch key {
 	case 0: // empty
 	case KeyEnter:
-		return []rune(c.Get()), c.Position, false
+		return c.line(), c.Position, false
 	case KeyBackspace:
 		if c.erase {
 			c.erase = false
 			c.Replace("")
 		}
 		c.Backspace()
+	case KeyDelete:
+		if c.erase {
+			c.erase = false
+			c.Replace("")
+		}
+		c.Delete()
+	case KeyDeleteWord:
+		if c.erase {
+			c.erase = false
+			c.Replace("")
+		}
+		c.DeleteWord()
+	case KeyKillToEnd, KeyKillToStart:
+		if c.erase {
+			c.erase = false
+			c.Replace("")
+		}
+		if key == KeyKillToEnd {
+			c.KillToEnd()
+		} else {
+			c.KillToStart()
+		}
 	case KeyForward:
 		// the user wants to edit the default, despite how we set it up. Let
 		// them.
@@ -208,6 +360,11 @@ func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
 		c.Move(1)
 	case KeyBackward:
 		c.Move(-1)
+	case KeyLineStart:
+		c.Place(c.lineStart(c.Position))
+	case KeyLineEnd:
+		c.erase = false
+		c.Place(c.lineEnd(c.Position))
 	default:
 		if c.erase {
 			c.erase = false
@@ -216,5 +373,13 @@ func (c *Cursor) Listen(line []rune, pos int, key rune) ([]rune, int, bool) {
 		}
 	}
 
-	return []rune(c.Get()), c.Position, true
+	return c.line(), c.Position, true
+}
+
+// line returns a copy of the input for readline, or nil when scrub is set.
+func (c *Cursor) line() []rune {
+	if c.scrub {
+		return nil
+	}
+	return []rune(c.Get())
 }
@@ -101,3 +101,256 @@ func TestCursor(t *testing.T) {
 		}
 	})
 }
+
+func TestCursorMultiline(t *testing.T) {
+	t.Run("Format at a line break", func(t *testing.T) {
+		cursor := Cursor{input: []rune("ab\ncd"), Cursor: pipeCursor}
+		cursor.Place(2)
+		if cursor.Format() != "ab|\ncd" {
+			t.Errorf("expected 'ab|\\ncd'; found %q", cursor.Format())
+		}
+
+		cursor.Cursor = defaultCursor
+		if cursor.Format() != "ab█\ncd" {
+			t.Errorf("expected 'ab█\\ncd'; found %q", cursor.Format())
+		}
+	})
+
+	t.Run("LineUp and LineDown", func(t *testing.T) {
+		cursor := Cursor{input: []rune("first\nab\nthird"), Cursor: pipeCursor}
+		cursor.End()
+
+		cursor.LineUp()
+		if cursor.Format() != "first\nab|\nthird" {
+			t.Errorf("expected 'first\\nab|\\nthird'; found %q", cursor.Format())
+		}
+
+		cursor.LineUp()
+		if cursor.Format() != "fi|rst\nab\nthird" {
+			t.Errorf("expected 'fi|rst\\nab\\nthird'; found %q", cursor.Format())
+		}
+
+		cursor.LineUp()
+		if cursor.Format() != "fi|rst\nab\nthird" {
+			t.Errorf("moved up from the first line; found %q", cursor.Format())
+		}
+
+		cursor.LineDown()
+		cursor.LineDown()
+		if cursor.Format() != "first\nab\nth|ird" {
+			t.Errorf("expected 'first\\nab\\nth|ird'; found %q", cursor.Format())
+		}
+
+		cursor.LineDown()
+		if cursor.Format() != "first\nab\nth|ird" {
+			t.Errorf("moved down from the last line; found %q", cursor.Format())
+		}
+	})
+}
+
+func TestCursorLineStartEnd(t *testing.T) {
+	t.Run("single line", func(t *testing.T) {
+		cursor := Cursor{input: []rune("hello"), Cursor: pipeCursor}
+		cursor.Place(2)
+
+		cursor.Listen(nil, 0, KeyLineStart)
+		if cursor.Format() != "|hello" {
+			t.Errorf("expected '|hello'; found %q", cursor.Format())
+		}
+
+		cursor.Listen(nil, 0, KeyLineEnd)
+		if cursor.Format() != "hello|" {
+			t.Errorf("expected 'hello|'; found %q", cursor.Format())
+		}
+		if cursor.Get() != "hello" {
+			t.Errorf("expected the input to be unchanged; found %q", cursor.Get())
+		}
+	})
+
+	t.Run("multiple lines", func(t *testing.T) {
+		cursor := Cursor{input: []rune("ab\ncd\nef"), Cursor: pipeCursor}
+		cursor.Place(4)
+
+		cursor.Listen(nil, 0, KeyLineStart)
+		if cursor.Format() != "ab\n|cd\nef" {
+			t.Errorf("expected 'ab\\n|cd\\nef'; found %q", cursor.Format())
+		}
+
+		cursor.Listen(nil, 0, KeyLineEnd)
+		if cursor.Format() != "ab\ncd|\nef" {
+			t.Errorf("expected 'ab\\ncd|\\nef'; found %q", cursor.Format())
+		}
+	})
+
+	t.Run("end keeps the default", func(t *testing.T) {
+		cursor := NewCursor("default", pipeCursor, true)
+		cursor.Listen(nil, 0, KeyLineEnd)
+		cursor.Listen(nil, 0, 'x')
+		if cursor.Get() != "default" {
+			t.Errorf("expected the default to be kept; found %q", cursor.Get())
+		}
+	})
+}
+
+func TestCursorDeleteWord(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		input    string
+		position int
+		expected string
+	}{
+		{scenario: "end of input", input: "git commit", position: 10, expected: "git |"},
+		{scenario: "trailing spaces", input: "git commit   ", position: 13, expected: "git |"},
+		{scenario: "spaces between words", input: "git   commit", position: 6, expected: "|commit"},
+		{scenario: "middle of a word", input: "git commit", position: 7, expected: "git |mit"},
+		{scenario: "single word", input: "git", position: 3, expected: "|"},
+		{scenario: "start of input", input: "git commit", position: 0, expected: "|git commit"},
+		{scenario: "empty input", input: "", position: 0, expected: "|"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune(tc.input), Cursor: pipeCursor}
+			cursor.Place(tc.position)
+			cursor.DeleteWord()
+			if cursor.Format() != tc.expected {
+				t.Errorf("expected %q; found %q", tc.expected, cursor.Format())
+			}
+		})
+	}
+
+	t.Run("Listen erases the default", func(t *testing.T) {
+		cursor := NewCursor("default value", pipeCursor, true)
+		cursor.Listen(nil, 0, KeyDeleteWord)
+		if cursor.Format() != "|" {
+			t.Errorf("expected '|'; found %q", cursor.Format())
+		}
+	})
+}
+
+func TestCursorDelete(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		position int
+		expected string
+	}{
+		{scenario: "start of input", position: 0, expected: "|bcdef"},
+		{scenario: "middle of input", position: 3, expected: "abc|ef"},
+		{scenario: "end of input", position: 6, expected: "abcdef|"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune("abcdef"), Cursor: pipeCursor}
+			cursor.Place(tc.position)
+			cursor.Listen(nil, 0, KeyDelete)
+			if cursor.Format() != tc.expected {
+				t.Errorf("expected %q; found %q", tc.expected, cursor.Format())
+			}
+		})
+	}
+
+	t.Run("Listen erases the default", func(t *testing.T) {
+		cursor := NewCursor("default value", pipeCursor, true)
+		cursor.Listen(nil, 0, KeyDelete)
+		if cursor.Format() != "|" {
+			t.Errorf("expected '|'; found %q", cursor.Format())
+		}
+	})
+}
+
+func TestCursorKill(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		key      rune
+		position int
+		expected string
+	}{
+		{scenario: "to end from the middle", key: KeyKillToEnd, position: 3, expected: "abc|"},
+		{scenario: "to end from the start", key: KeyKillToEnd, position: 0, expected: "|"},
+		{scenario: "to end from the end", key: KeyKillToEnd, position: 6, expected: "abcdef|"},
+		{scenario: "to start from the middle", key: KeyKillToStart, position: 3, expected: "|def"},
+		{scenario: "to start from the start", key: KeyKillToStart, position: 0, expected: "|abcdef"},
+		{scenario: "to start from the end", key: KeyKillToStart, position: 6, expected: "|"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cursor := Cursor{input: []rune("abcdef"), Cursor: pipeCursor}
+			cursor.Place(tc.position)
+			cursor.Listen(nil, 0, tc.key)
+			if cursor.Format() != tc.expected {
+				t.Errorf("expected %q; found %q", tc.expected, cursor.Format())
+			}
+		})
+	}
+
+	t.Run("multiple lines", func(t *testing.T) {
+		cursor := Cursor{input: []rune("ab\ncdef\ngh"), Cursor: pipeCursor}
+		cursor.Place(5)
+
+		cursor.KillToEnd()
+		if cursor.Format() != "ab\ncd|\ngh" {
+			t.Errorf("expected 'ab\\ncd|\\ngh'; found %q", cursor.Format())
+		}
+
+		cursor.KillToStart()
+		if cursor.Format() != "ab\n|\ngh" {
+			t.Errorf("expected 'ab\\n|\\ngh'; found %q", cursor.Format())
+		}
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		cursor := Cursor{input: []rune("secret"), Cursor: pipeCursor}
+		cursor.Place(2)
+		cursor.KillToStart()
+		if cursor.FormatMask('*') != "|****" {
+			t.Errorf("expected '|****'; found %q", cursor.FormatMask('*'))
+		}
+	})
+}
+
+func TestCursorScrub(t *testing.T) {
+	zeroed := func(r []rune) bool {
+		for _, c := range r {
+			if c != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	cursor := NewCursor("", pipeCursor, false)
+	cursor.scrub = true
+	for _, r := range "secret" {
+		cursor.Listen([]rune{r}, 0, r)
+	}
+	if cursor.Get() != "secret" {
+		t.Fatalf("expected 'secret'; found %q", cursor.Get())
+	}
+
+	word := cursor.input[:cap(cursor.input)]
+	cursor.Update(" word")
+	if !zeroed(word) {
+		t.Errorf("expected the previous input to be zeroed; found %q", string(word))
+	}
+
+	tail := cursor.input[:cap(cursor.input)]
+	cursor.DeleteWord()
+	if cursor.Get() != "secret " {
+		t.Errorf("expected 'secret '; found %q", cursor.Get())
+	}
+	if !zeroed(tail[len(cursor.input):]) {
+		t.Errorf("expected the deleted word to be zeroed; found %q", string(tail))
+	}
+
+	if line, _, _ := cursor.Listen(nil, 0, KeyEnter); line != nil {
+		t.Errorf("expected no copy of the input; found %q", string(line))
+	}
+
+	all := cursor.input[:cap(cursor.input)]
+	cursor.clear()
+	if !zeroed(all) || cursor.Get() != "" {
+		t.Errorf("expected the input to be zeroed; found %q", string(all))
+	}
+}
@@ -0,0 +1,25 @@
+package promptui
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// keyLoneEsc replaces a lone escape in the input of a select. Readline reads an escape as the start of an escape
+// sequence and waits for the following key, so a lone press of the escape key would otherwise only be seen with
+// the next key.
+const keyLoneEsc = '\uE01B'
+
+// escapeReader replaces the reads made of a single escape by keyLoneEsc. The escape sequences sent by the other
+// keys, like the arrows, are read at once and are left untouched.
+type escapeReader struct {
+	io.ReadCloser
+}
+
+func (r escapeReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	if n == 1 && b[0] == byte(KeyEsc) && len(b) >= utf8.UTFMax {
+		n = utf8.EncodeRune(b, keyLoneEsc)
+	}
+	return n, err
+}
@@ -0,0 +1,54 @@
+package promptui
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestEscapeReader(t *testing.T) {
+	tcs := []struct {
+		name   string
+		chunks []string
+		exp    string
+	}{
+		{name: "lone escape", chunks: []string{"\x1b"}, exp: string(keyLoneEsc)},
+		{name: "escape sequence", chunks: []string{"\x1b[A"}, exp: "\x1b[A"},
+		{name: "escape between keys", chunks: []string{"a", "\x1b", "b"}, exp: "a" + string(keyLoneEsc) + "b"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := escapeReader{ioutil.NopCloser(&chunkReader{chunks: tc.chunks})}
+
+			b := make([]byte, 16)
+			var got strings.Builder
+			for {
+				n, err := r.Read(b)
+				got.Write(b[:n])
+				if err != nil {
+					break
+				}
+			}
+
+			if got.String() != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, got.String())
+			}
+		})
+	}
+}
+
+// chunkReader returns one of its chunks on each read, like the keys pressed on a terminal.
+type chunkReader struct {
+	chunks []string
+}
+
+func (r *chunkReader) Read(b []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
@@ -0,0 +1,24 @@
+package promptui
+
+// InputFilter is a function called for each character typed in a prompt with the position where it would be
+// inserted. If it returns false, the character is dropped before reaching the input.
+type InputFilter func(r rune, pos int) bool
+
+// FilterDigits is an InputFilter only accepting the digits 0 to 9, for example to enter counts or ports.
+func FilterDigits(r rune, pos int) bool {
+	return r >= '0' && r <= '9'
+}
+
+// FilterFloat is an InputFilter only accepting the characters of a decimal number: digits, a decimal point
+// and a sign at the start of the input. It does not check the number as a whole, for example a second decimal
+// point is still accepted, which should be handled by a validation function.
+func FilterFloat(r rune, pos int) bool {
+	switch {
+	case FilterDigits(r, pos), r == '.':
+		return true
+	case r == '-' || r == '+':
+		return pos == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package promptui
+
+import "testing"
+
+func TestFilters(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		filter   InputFilter
+		r        rune
+		pos      int
+		expect   bool
+	}{
+		{scenario: "digits accepts a digit", filter: FilterDigits, r: '7', expect: true},
+		{scenario: "digits rejects a letter", filter: FilterDigits, r: 'a', expect: false},
+		{scenario: "digits rejects a sign", filter: FilterDigits, r: '-', expect: false},
+		{scenario: "float accepts a digit", filter: FilterFloat, r: '7', pos: 3, expect: true},
+		{scenario: "float accepts a decimal point", filter: FilterFloat, r: '.', pos: 1, expect: true},
+		{scenario: "float accepts a leading sign", filter: FilterFloat, r: '-', pos: 0, expect: true},
+		{scenario: "float rejects a sign inside the number", filter: FilterFloat, r: '-', pos: 2, expect: false},
+		{scenario: "float rejects a letter", filter: FilterFloat, r: 'e', pos: 1, expect: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if got := tc.filter(tc.r, tc.pos); got != tc.expect {
+				t.Errorf("expected %t, got %t", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package promptui
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/logrhythm/promptui/list"
+)
+
+// SelectGroup is a named section of items inside a Select. See the Select Groups docs for more info.
+type SelectGroup struct {
+	// Name is the text displayed in the header line before the items of the group, using the Header template.
+	Name string
+
+	// Items are the items of the group. It expect a slice of any kind of values, like the Items of a Select.
+	Items interface{}
+}
+
+// lazyPages is the number of pages of Size items loaded at once with the ItemsFunc of a select.
+const lazyPages = 4
+
+// groupHeader is the list entry displaying the header line of a SelectGroup.
+type groupHeader struct {
+	name string
+}
+
+// newList creates the list of the select from its items. When groups are used, the list holds the header line
+// of each group followed by its items, and s.itemOf maps each entry of the list to the index of its item among
+// the items of all groups.
+func (s *Select) newList() (*list.List, error) {
+	s.items = nil
+	s.itemOf = nil
+	s.lazy = nil
+
+	entries := s.Items
+	if s.ItemsFunc != nil && s.Groups != nil {
+		return nil, fmt.Errorf("items of groups cannot be loaded with ItemsFunc")
+	} else if s.ItemsFunc != nil {
+		lazy, err := list.NewLazy(s.ItemsFunc, s.Size, lazyPages*s.Size)
+		if err != nil {
+			return nil, err
+		}
+		s.lazy = lazy
+		return s.setupList(lazy.List), nil
+	} else if s.Groups != nil {
+		var flat []interface{}
+		for _, g := range s.Groups {
+			if g.Items == nil || reflect.TypeOf(g.Items).Kind() != reflect.Slice {
+				return nil, fmt.Errorf("items %v of group %q is not a slice", g.Items, g.Name)
+			}
+
+			flat = append(flat, groupHeader{name: g.Name})
+			s.itemOf = append(s.itemOf, list.NotFound)
+
+			items := reflect.ValueOf(g.Items)
+			for i := 0; i < items.Len(); i++ {
+				s.itemOf = append(s.itemOf, len(s.items))
+				s.items = append(s.items, items.Index(i).Interface())
+				flat = append(flat, s.items[len(s.items)-1])
+			}
+		}
+		entries = flat
+	}
+
+	l, err := list.New(entries, s.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setupList(l), nil
+}
+
+// setupList configures the search, navigation and highlighting of the list of the select.
+func (s *Select) setupList(l *list.List) *list.List {
+	l.Wrap = s.WrapNavigation
+
+	if s.Groups != nil || s.Disabled != nil {
+		l.Disabled = func(entry int) bool {
+			i := s.itemIndex(entry)
+			return i == list.NotFound || s.isDisabled(i)
+		}
+	}
+
+	search := s.Searcher
+	if search == nil && s.Scorer == nil && s.Project != nil {
+		search = SubstringSearcher(func(index int) string {
+			label, _ := s.Project(index)
+			return label
+		})
+	}
+
+	if s.Scorer != nil && s.Groups == nil {
+		l.SetScorer(s.Scorer)
+	} else if s.Scorer != nil {
+		search = func(input string, index int) bool {
+			_, matched := s.Scorer(input, index)
+			return matched
+		}
+	}
+
+	if s.Matcher != nil {
+		l.SetMatcher(func(term string, entry int) []int {
+			if i := s.itemIndex(entry); i != list.NotFound {
+				return s.Matcher(term, i)
+			}
+			return nil
+		})
+	}
+
+	if search != nil {
+		l.Searcher = func(input string, entry int) bool {
+			if s.itemOf == nil || s.itemOf[entry] != list.NotFound {
+				return search(input, s.itemIndex(entry))
+			}
+
+			// a header is kept as long as one of the items of its group is kept
+			for e := entry + 1; e < len(s.itemOf) && s.itemOf[e] != list.NotFound; e++ {
+				if search(input, s.itemOf[e]) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return l
+}
+
+// itemIndex returns the index of the item displayed by the given entry of the list, or NotFound for a group
+// header.
+func (s *Select) itemIndex(entry int) int {
+	if s.itemOf == nil || entry == list.NotFound {
+		return entry
+	}
+	return s.itemOf[entry]
+}
+
+// itemCount returns the number of items of the select, including the items of all groups or the items loaded
+// with ItemsFunc.
+func (s *Select) itemCount() int {
+	switch {
+	case s.lazy != nil:
+		return s.list.Len()
+	case s.Groups != nil:
+		return len(s.items)
+	}
+	return reflect.ValueOf(s.Items).Len()
+}
+
+// empty reports whether the select has no item to select, nil Items included. The items loaded with ItemsFunc
+// are not known up front, so they are never empty.
+func (s *Select) empty() bool {
+	switch {
+	case s.ItemsFunc != nil:
+		return false
+	case s.Groups != nil:
+		for _, g := range s.Groups {
+			// the items that are not a slice are reported by newList.
+			items := reflect.ValueOf(g.Items)
+			if items.Kind() != reflect.Slice || items.Len() > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return s.Items == nil || reflect.TypeOf(s.Items).Kind() == reflect.Slice && reflect.ValueOf(s.Items).Len() == 0
+}
+
+// item returns the item at the given index, including the items of all groups or the items loaded with
+// ItemsFunc.
+func (s *Select) item(index int) interface{} {
+	switch {
+	case s.lazy != nil:
+		return s.list.Item(index)
+	case s.Groups != nil:
+		return s.items[index]
+	}
+	return reflect.ValueOf(s.Items).Index(index).Interface()
+}
@@ -0,0 +1,75 @@
+package promptui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectGroups(t *testing.T) {
+	newSelect := func() *Select {
+		s := &Select{
+			Size: 10,
+			Groups: []SelectGroup{
+				{Name: "Fruits", Items: []string{"apple", "banana"}},
+				{Name: "Vegetables", Items: []string{"carrot", "pea"}},
+			},
+			Searcher: func(input string, idx int) bool {
+				return strings.Contains([]string{"apple", "banana", "carrot", "pea"}[idx], input)
+			},
+		}
+
+		l, err := s.newList()
+		if err != nil {
+			t.Fatalf("Unexpected error creating the list %v", err)
+		}
+		s.list = l
+		return s
+	}
+
+	t.Run("when moving through the list", func(t *testing.T) {
+		s := newSelect()
+		s.list.SetCursor(0)
+
+		var got []int
+		for i := 0; i < 4; i++ {
+			got = append(got, s.itemIndex(s.list.Index()))
+			s.list.Next()
+		}
+
+		if !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+			t.Errorf("Expected the items to be selected in order skipping headers, got %v", got)
+		}
+
+		if s.item(2) != "carrot" {
+			t.Errorf("Expected item 2 to be carrot, got %v", s.item(2))
+		}
+	})
+
+	t.Run("when searching", func(t *testing.T) {
+		s := newSelect()
+		s.list.Search("rr")
+
+		items, _ := s.list.Items()
+		exp := []interface{}{groupHeader{name: "Vegetables"}, "carrot"}
+		if !reflect.DeepEqual(items, exp) {
+			t.Errorf("Expected %v, got %v", exp, items)
+		}
+
+		if got := s.itemIndex(s.list.Index()); got != 2 {
+			t.Errorf("Expected the cursor on item 2, got %d", got)
+		}
+
+		if s.total() != 4 || s.matchedCount() != 1 {
+			t.Errorf("Expected 1 of 4 items matched, got %d of %d", s.matchedCount(), s.total())
+		}
+	})
+
+	t.Run("when the items of a group are not a slice", func(t *testing.T) {
+		s := &Select{Size: 5, Groups: []SelectGroup{{Name: "Fruits", Items: "apple"}}}
+		_, err := s.newList()
+		if err == nil {
+			t.Errorf("Expected error got none")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package promptui
+
+// history tracks the navigation of a prompt through its previous entries. The
+// entries are copied so navigating never modifies the slice given by the user.
+type history struct {
+	entries []string
+
+	// index is the entry currently displayed. It is equal to len(entries) when
+	// the in-progress input is displayed.
+	index int
+
+	// current is the in-progress input, saved when navigating away from it.
+	current string
+}
+
+func newHistory(entries []string) *history {
+	h := &history{entries: make([]string, len(entries))}
+	copy(h.entries, entries)
+	h.index = len(h.entries)
+	return h
+}
+
+// prev returns the entry preceding the one currently displayed. The given
+// input is saved and restored once navigating back to the end of the history.
+// If the first entry is already displayed, false is returned.
+func (h *history) prev(input string) (string, bool) {
+	if h.index == 0 {
+		return "", false
+	}
+
+	if h.index == len(h.entries) {
+		h.current = input
+	}
+
+	h.index--
+	return h.entries[h.index], true
+}
+
+// next returns the entry following the one currently displayed, or the
+// in-progress input when moving past the last entry. If the in-progress input
+// is already displayed, false is returned.
+func (h *history) next() (string, bool) {
+	if h.index >= len(h.entries) {
+		return "", false
+	}
+
+	h.index++
+	if h.index == len(h.entries) {
+		return h.current, true
+	}
+	return h.entries[h.index], true
+}
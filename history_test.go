@@ -0,0 +1,50 @@
+package promptui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	entries := []string{"one", "two", "three"}
+	h := newHistory(entries)
+
+	if _, ok := h.next(); ok {
+		t.Errorf("expected no entry after the in-progress input")
+	}
+
+	tcs := []struct {
+		move   string
+		expect string
+		ok     bool
+	}{
+		{move: "prev", expect: "three", ok: true},
+		{move: "prev", expect: "two", ok: true},
+		{move: "prev", expect: "one", ok: true},
+		{move: "prev", ok: false},
+		{move: "next", expect: "two", ok: true},
+		{move: "next", expect: "three", ok: true},
+		{move: "next", expect: "in progress", ok: true},
+		{move: "next", ok: false},
+	}
+
+	for i, tc := range tcs {
+		var got string
+		var ok bool
+
+		switch tc.move {
+		case "prev":
+			got, ok = h.prev("in progress")
+		case "next":
+			got, ok = h.next()
+		}
+
+		if ok != tc.ok || got != tc.expect {
+			t.Errorf("move %d (%s): expected (%q, %t), got (%q, %t)", i, tc.move, tc.expect, tc.ok, got, ok)
+		}
+	}
+
+	if !reflect.DeepEqual(entries, []string{"one", "two", "three"}) {
+		t.Errorf("expected entries to be unchanged, got %v", entries)
+	}
+}
@@ -0,0 +1,115 @@
+package promptui
+
+import "text/template"
+
+// IconSet defines the icons displayed by the default templates of the prompts and selects. A set can be given
+// to a Prompt or Select through their Icons field, and its icons are available inside the templates with the
+// iconInitial, iconGood, iconWarn, iconBad and iconSelect functions of FuncMap.
+type IconSet struct {
+	// Initial is the icon used when starting in prompt mode and the icon next to the label when starting in
+	// select mode.
+	Initial string
+
+	// Good is the icon used when a good answer is entered in prompt mode.
+	Good string
+
+	// Warn is the icon used when a good, but potentially invalid answer is entered in prompt mode.
+	Warn string
+
+	// Bad is the icon used when a bad answer is entered in prompt mode.
+	Bad string
+
+	// Select is the icon used to identify the currently selected item in select mode.
+	Select string
+}
+
+// UnicodeIcons returns the icon set made of unicode glyphs, styled unless the colors are disabled. It is the
+// default icon set on all platforms but Windows.
+func UnicodeIcons() IconSet {
+	return IconSet{
+		Initial: Styler(FGBlue)("?"),
+		Good:    Styler(FGGreen)("✔"),
+		Warn:    Styler(FGYellow)("⚠"),
+		Bad:     Styler(FGRed)("✗"),
+		Select:  Styler(FGBold)("▸"),
+	}
+}
+
+// ASCIIIcons returns the icon set made of ASCII characters only, styled unless the colors are disabled. It is the
+// default icon set on Windows and renders on terminals and fonts lacking the unicode glyphs.
+func ASCIIIcons() IconSet {
+	return IconSet{
+		Initial: Styler(FGBlue)("?"),
+		Good:    Styler(FGGreen)("v"),
+		Warn:    Styler(FGYellow)("!"),
+		Bad:     Styler(FGRed)("x"),
+		Select:  Styler(FGBold)(">"),
+	}
+}
+
+// defaultIcons returns the icon set made of the current value of the package icons, such as IconInitial.
+func defaultIcons() IconSet {
+	return IconSet{
+		Initial: IconInitial,
+		Good:    IconGood,
+		Warn:    IconWarn,
+		Bad:     IconBad,
+		Select:  IconSelect,
+	}
+}
+
+// useIcons sets the package icons to the icons of the given set.
+func useIcons(icons IconSet) {
+	IconInitial = icons.Initial
+	IconGood = icons.Good
+	IconWarn = icons.Warn
+	IconBad = icons.Bad
+	IconSelect = icons.Select
+}
+
+// activeIcons returns the given icon set, or the package icons when it is nil.
+func activeIcons(icons *IconSet) IconSet {
+	if icons == nil {
+		return defaultIcons()
+	}
+	return *icons
+}
+
+// withIcons returns the template functions with the icon functions rendering the given icon set, which the default
+// templates use. When the set is nil, the icon functions of funcs are kept, or else render the package icons.
+func withIcons(funcs template.FuncMap, icons *IconSet) template.FuncMap {
+	merged := template.FuncMap{}
+	for name, fn := range packageIconFuncs() {
+		merged[name] = fn
+	}
+	for name, fn := range funcs {
+		merged[name] = fn
+	}
+	if icons != nil {
+		for name, fn := range icons.funcs() {
+			merged[name] = fn
+		}
+	}
+	return merged
+}
+
+// packageIconFuncs returns the icon functions rendering the current value of the package icons, like FuncMap.
+func packageIconFuncs() template.FuncMap {
+	return template.FuncMap{
+		"iconInitial": func() string { return IconInitial },
+		"iconGood":    func() string { return IconGood },
+		"iconWarn":    func() string { return IconWarn },
+		"iconBad":     func() string { return IconBad },
+		"iconSelect":  func() string { return IconSelect },
+	}
+}
+
+func (i IconSet) funcs() template.FuncMap {
+	return template.FuncMap{
+		"iconInitial": func() string { return i.Initial },
+		"iconGood":    func() string { return i.Good },
+		"iconWarn":    func() string { return i.Warn },
+		"iconBad":     func() string { return i.Bad },
+		"iconSelect":  func() string { return i.Select },
+	}
+}
@@ -0,0 +1,100 @@
+package promptui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIconSet(t *testing.T) {
+	ascii := ASCIIIcons()
+
+	t.Run("default templates use the icon set", func(t *testing.T) {
+		s := &Select{Label: "Pick", Icons: &ascii}
+		err := s.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		label := string(render(s.Templates.label, "Pick"))
+		expected := ascii.Initial + " Pick: "
+		if label != expected {
+			t.Errorf("Expected label %q, got %q", expected, label)
+		}
+
+		active := string(render(s.Templates.active, "item"))
+		expected = ascii.Select + " " + Styler(FGUnderline)("item")
+		if active != expected {
+			t.Errorf("Expected active %q, got %q", expected, active)
+		}
+	})
+
+	t.Run("icon functions render the icon set", func(t *testing.T) {
+		icons := IconSet{Good: "+", Bad: "-"}
+		p := &Prompt{
+			Label: "Name",
+			Icons: &icons,
+			Templates: &PromptTemplates{
+				Valid:   "{{ iconGood }} {{ . }}",
+				Invalid: "{{ iconBad }} {{ . }}",
+			},
+		}
+		err := p.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if valid := string(render(p.Templates.valid, "Name")); valid != "+ Name" {
+			t.Errorf("Expected valid %q, got %q", "+ Name", valid)
+		}
+		if invalid := string(render(p.Templates.invalid, "Name")); invalid != "- Name" {
+			t.Errorf("Expected invalid %q, got %q", "- Name", invalid)
+		}
+	})
+
+	t.Run("icon functions default to the package icons", func(t *testing.T) {
+		s := &Select{Label: "Pick", Templates: &SelectTemplates{Label: "{{ iconInitial }} {{ . }}"}}
+		err := s.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		label := string(render(s.Templates.label, "Pick"))
+		expected := IconInitial + " Pick"
+		if label != expected {
+			t.Errorf("Expected label %q, got %q", expected, label)
+		}
+	})
+	t.Run("icon set changed between runs", func(t *testing.T) {
+		defer DisableColors(colorsDisabled)
+		DisableColors(true)
+
+		unicode, ascii := UnicodeIcons(), ASCIIIcons()
+		p := Prompt{Label: "Pepper", Icons: &unicode}
+		s := Select{Label: "Pepper", Items: []string{"Bell"}, HideHelp: true, Icons: &unicode}
+
+		for _, icons := range []*IconSet{&unicode, &ascii} {
+			p.Icons, s.Icons = icons, icons
+
+			term := NewTestTerminal("Bell", "\r")
+			p.Terminal, p.Stdin, p.Stdout = term, term, term
+			if _, err := p.Run(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if exp := icons.Good + " Pepper: Bell"; !strings.Contains(term.Output(), exp) {
+				t.Errorf("Expected the prompt with %q, got %q", exp, term.Output())
+			}
+
+			term = NewTestTerminal("\r")
+			s.Terminal, s.Stdin, s.Stdout = term, term, term
+			if _, _, err := s.Run(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if exp := icons.Select + " Bell"; !strings.Contains(term.Output(), exp) {
+				t.Errorf("Expected the active item with %q, got %q", exp, term.Output())
+			}
+			if exp := icons.Good + " Bell"; !strings.Contains(term.Output(), exp) {
+				t.Errorf("Expected the selected item with %q, got %q", exp, term.Output())
+			}
+		}
+	})
+}
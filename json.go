@@ -0,0 +1,90 @@
+package promptui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONItem is an item of a select created by SelectFromJSON. The templates of the select can display its fields,
+// like `{{ .Display }}` or `{{ .Value }}`, and the default ones display its Display string.
+type JSONItem struct {
+	// Value is the value of the item for the program, which is not displayed by the default templates.
+	Value string `json:"value"`
+
+	// Display is the string displayed for the item and returned by Run. Defaults to Value.
+	Display string `json:"display"`
+
+	// Details are displayed below the list when the item is active. They can have multiple lines.
+	Details string `json:"details"`
+}
+
+// SelectLabel returns the Display string of the item, or its Value if it has none.
+func (i JSONItem) SelectLabel() string {
+	if i.Display == "" {
+		return i.Value
+	}
+	return i.Display
+}
+
+// SelectDetails returns the Details of the item.
+func (i JSONItem) SelectDetails() string {
+	return i.Details
+}
+
+// jsonSelect is the schema decoded by SelectFromJSON.
+type jsonSelect struct {
+	Label   string     `json:"label"`
+	Items   []JSONItem `json:"items"`
+	Size    int        `json:"size"`
+	VimMode bool       `json:"vim_mode"`
+}
+
+// SelectFromJSON creates a select from its definition in JSON, for menus defined in configuration files. The
+// definition is an object with the label of the select, its items, with a value, a display string and details
+// each, its size and whether it runs in vim mode:
+//
+//	{
+//		"label": "Pepper",
+//		"items": [
+//			{"value": "bell", "display": "Bell Pepper", "details": "Heat: 0"},
+//			{"value": "habanero", "display": "Habanero", "details": "Heat: 100000"}
+//		],
+//		"size": 5,
+//		"vim_mode": false
+//	}
+//
+// Only the items are required, and each of them needs a value. The Items of the select are a []JSONItem, so the
+// value of the chosen item is found with the index returned by Run:
+//
+//	value := s.Items.([]promptui.JSONItem)[i].Value
+//
+// An error is returned if the definition is not valid JSON, has unknown fields or misses a required one.
+func SelectFromJSON(r io.Reader) (*Select, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var def jsonSelect
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid select definition: %v", err)
+	}
+
+	if len(def.Items) == 0 {
+		return nil, fmt.Errorf("invalid select definition: no items")
+	}
+	for i, item := range def.Items {
+		if item.Value == "" {
+			return nil, fmt.Errorf("invalid select definition: item %d has no value", i)
+		}
+	}
+	if def.Size < 0 {
+		return nil, fmt.Errorf("invalid select definition: negative size %d", def.Size)
+	}
+
+	return &Select{
+		Label:     def.Label,
+		Items:     def.Items,
+		Size:      def.Size,
+		IsVimMode: def.VimMode,
+	}, nil
+}
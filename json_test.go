@@ -0,0 +1,72 @@
+package promptui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectFromJSON(t *testing.T) {
+	def := `{
+		"label": "Pepper",
+		"items": [
+			{"value": "bell", "display": "Bell Pepper", "details": "Heat: 0"},
+			{"value": "habanero", "details": "Heat: 100000"}
+		],
+		"size": 3,
+		"vim_mode": true
+	}`
+
+	s, err := SelectFromJSON(strings.NewReader(def))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	items := []JSONItem{
+		{Value: "bell", Display: "Bell Pepper", Details: "Heat: 0"},
+		{Value: "habanero", Details: "Heat: 100000"},
+	}
+	if s.Label != "Pepper" || s.Size != 3 || !s.IsVimMode || !reflect.DeepEqual(s.Items, items) {
+		t.Errorf("Expected the select of the definition, got %+v", s)
+	}
+
+	term := NewTestTerminal("j", "\r")
+	s.Terminal, s.Stdin, s.Stdout = term, term, term
+	s.Templates = &SelectTemplates{Selected: "{{ .Display }} ({{ .Value }})"}
+
+	i, value, err := s.Run()
+	if i != 1 || value != "habanero" || err != nil {
+		t.Errorf("Expected 1, %q and no error, got %d, %q and %v", "habanero", i, value, err)
+	}
+	if !strings.Contains(term.Output(), " (habanero)") {
+		t.Errorf("Expected the value rendered by the template in %q", term.Output())
+	}
+}
+
+func TestSelectFromJSONInvalid(t *testing.T) {
+	tcs := []struct {
+		name string
+		def  string
+		err  string
+	}{
+		{name: "syntax", def: `{"items": [`, err: "invalid select definition: unexpected EOF"},
+		{name: "unknown field", def: `{"items": [{"value": "bell"}], "color": "red"}`,
+			err: `invalid select definition: json: unknown field "color"`},
+		{name: "wrong type", def: `{"items": [{"value": "bell"}], "size": "5"}`,
+			err: "invalid select definition: json: cannot unmarshal string"},
+		{name: "no items", def: `{"label": "Pepper"}`, err: "invalid select definition: no items"},
+		{name: "no value", def: `{"items": [{"value": "bell"}, {"display": "Habanero"}]}`,
+			err: "invalid select definition: item 1 has no value"},
+		{name: "negative size", def: `{"items": [{"value": "bell"}], "size": -1}`,
+			err: "invalid select definition: negative size -1"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := SelectFromJSON(strings.NewReader(tc.def))
+			if s != nil || err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Errorf("Expected the error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
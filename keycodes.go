@@ -10,9 +10,34 @@ var (
 	// KeyEnter is the default key for submission/selection.
 	KeyEnter rune = readline.CharEnter
 
+	// KeyTab is the default key for completing the input of a prompt with its suggestions.
+	KeyTab rune = readline.CharTab
+
 	// KeyBackspace is the default key for deleting input text.
 	KeyBackspace rune = readline.CharBackspace
 
+	// KeyDeleteWord is the default key for deleting the word preceding the cursor.
+	KeyDeleteWord rune = readline.CharCtrlW
+
+	// KeyKillToEnd is the default key for deleting the input from the cursor to the end of the line.
+	KeyKillToEnd rune = readline.CharKill
+
+	// KeyKillToStart is the default key for deleting the input from the start of the line to the cursor.
+	KeyKillToStart rune = readline.CharCtrlU
+
+	// KeyLineStart is the default key for moving the cursor to the start of the line, also sent by <Home>.
+	KeyLineStart rune = readline.CharLineStart
+
+	// KeyLineEnd is the default key for moving the cursor to the end of the line, also sent by <End>.
+	KeyLineEnd rune = readline.CharLineEnd
+
+	// KeyRedraw is the default key for clearing the screen and drawing the prompt or the select again.
+	KeyRedraw rune = readline.CharCtrlL
+
+	// KeyEsc is the default key for clearing the searched term during selection.
+	KeyEsc        rune = readline.CharEsc
+	KeyEscDisplay      = "esc"
+
 	// KeyPrev is the default key to go up during selection.
 	KeyPrev        rune = readline.CharPrev
 	KeyPrevDisplay      = "↑"
@@ -6,9 +6,39 @@ var (
 	// KeyEnter is the default key for submission/selection inside a command line prompt.
 	KeyEnter rune = 13
 
+	// KeyTab is the default key for completing the input of a prompt with its suggestions.
+	KeyTab rune = 9
+
 	// KeyBackspace is the default key for deleting input text inside a command line prompt.
 	KeyBackspace rune = 8
 
+	// KeyDeleteWord is the default key for deleting the word preceding the cursor inside a command line prompt.
+	KeyDeleteWord rune = 23
+
+	// KeyKillToEnd is the default key for deleting the input from the cursor to the end of the line inside a
+	// command line prompt.
+	KeyKillToEnd rune = 11
+
+	// KeyKillToStart is the default key for deleting the input from the start of the line to the cursor inside
+	// a command line prompt.
+	KeyKillToStart rune = 21
+
+	// KeyLineStart is the default key for moving the cursor to the start of the line inside a command line
+	// prompt, also sent by <Home>.
+	KeyLineStart rune = 1
+
+	// KeyLineEnd is the default key for moving the cursor to the end of the line inside a command line prompt,
+	// also sent by <End>.
+	KeyLineEnd rune = 5
+
+	// KeyRedraw is the default key for clearing the screen and drawing the prompt or the select again inside a
+	// command line prompt.
+	KeyRedraw rune = 12
+
+	// KeyEsc is the default key for clearing the searched term during selection inside a command line prompt.
+	KeyEsc        rune = 27
+	KeyEscDisplay      = "esc"
+
 	// FIXME: keys below are not triggered by readline, not working on Windows
 
 	// KeyPrev is the default key to go up during selection inside a command line prompt.
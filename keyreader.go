@@ -0,0 +1,213 @@
+package promptui
+
+import (
+	"io"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// KeyPageUp and KeyPageDown are read for the <PageUp> and <PageDown> keys, which have no rune in readline. They
+// are in the private use area of unicode, so they cannot be typed otherwise. During selection, they page up and
+// down like the PageUp and PageDown keys of SelectKeys.
+const (
+	KeyPageUp   rune = '\uE050'
+	KeyPageDown rune = '\uE051'
+)
+
+// KeyDelete is read for the <Delete> key, which deletes the rune under the cursor of a prompt. Readline reads it
+// as <Ctrl+D>, which ends a prompt when its input is empty.
+const KeyDelete rune = '\uE07F'
+
+// ss3 starts the sequences sent by some terminals for the arrows, <Home> and <End>, instead of esc.
+const ss3 = "\033O"
+
+// KeyReader reads the keys pressed in a terminal from its input. The escape sequences of the arrows, <Home>,
+// <End>, <Delete>, <PageUp> and <PageDown> differ between terminals, and are decoded into a single rune each:
+//
+//	readline.CharPrev, readline.CharNext         the up and down arrows
+//	readline.CharBackward, readline.CharForward  the left and right arrows
+//	KeyLineStart, KeyLineEnd                     <Home> and <End>
+//	KeyDelete                                    <Delete>
+//	KeyPageUp, KeyPageDown                       <PageUp> and <PageDown>
+//
+// The modifiers held with these keys, like <Ctrl> or <Shift>, are ignored. These are the runes readline reads for
+// the same keys on Windows consoles. The printable runes and the control keys, like <Ctrl-W>, are read as is, as
+// are the other escape sequences, rune by rune.
+//
+// Prompts and selects read their input through a KeyReader, which is also an io.ReadCloser of the decoded keys.
+type KeyReader struct {
+	r io.Reader
+
+	// pending holds an incomplete sequence at the end of the previous read, keys the decoded keys not read yet
+	// and out the keys encoded by Read that did not fit in b.
+	pending []byte
+	keys    []rune
+	out     []byte
+	buf     []byte
+}
+
+// NewKeyReader creates a KeyReader reading the keys from r.
+func NewKeyReader(r io.Reader) *KeyReader {
+	return &KeyReader{r: r}
+}
+
+// ReadKey returns the next key.
+func (k *KeyReader) ReadKey() (rune, error) {
+	for len(k.keys) == 0 {
+		if err := k.fill(); err != nil && len(k.keys) == 0 {
+			return 0, err
+		}
+	}
+
+	r := k.keys[0]
+	k.keys[0] = 0
+	k.keys = k.keys[1:]
+	return r, nil
+}
+
+// Read reads the decoded keys encoded in utf-8. The keys of a single read of the input are returned together,
+// so a lone escape is still read alone.
+func (k *KeyReader) Read(b []byte) (int, error) {
+	for len(k.out) == 0 {
+		err := k.fill()
+		for i, r := range k.keys {
+			k.out = append(k.out, string(r)...)
+			k.keys[i] = 0
+		}
+		k.keys = nil
+
+		if err != nil && len(k.out) == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(b, k.out)
+	zeroBytes(k.out[:n])
+	k.out = k.out[n:]
+	return n, nil
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (k *KeyReader) Close() error {
+	if c, ok := k.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// fill decodes the keys of the next read of the input.
+func (k *KeyReader) fill() error {
+	if len(k.buf) == 0 {
+		k.buf = make([]byte, 256)
+	}
+
+	n, err := k.r.Read(k.buf)
+	data := append(k.pending, k.buf[:n]...)
+	k.pending = nil
+	k.decode(data, err != nil)
+
+	// the input is cleared once decoded, so the keys do not linger in memory. The pending sequence is kept in data.
+	zeroBytes(k.buf[:n])
+	zeroBytes(data[:len(data)-len(k.pending)])
+	return err
+}
+
+// clear zeroes and drops the keys not read yet. The keys already read are zeroed as they are read.
+func (k *KeyReader) clear() {
+	zeroBytes(k.pending)
+	zeroBytes(k.out)
+	for i := range k.keys {
+		k.keys[i] = 0
+	}
+	k.pending, k.keys, k.out = nil, nil, nil
+}
+
+// zeroBytes overwrites b with zeros.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// decode appends the keys of data to keys. Unless final is set, an incomplete sequence or rune at the end of data
+// is kept pending until the next read. A lone escape is never kept, since it is otherwise the escape key.
+func (k *KeyReader) decode(data []byte, final bool) {
+	for i := 0; i < len(data); {
+		if !final && !utf8.FullRune(data[i:]) {
+			k.pending = data[i:]
+			return
+		}
+
+		if data[i] == '\033' {
+			n, key, complete := keySequence(data[i:])
+			if !complete && !final {
+				k.pending = data[i:]
+				return
+			}
+			if n > 0 {
+				k.keys = append(k.keys, key)
+				i += n
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRune(data[i:])
+		k.keys = append(k.keys, r)
+		i += size
+	}
+}
+
+// keySequence decodes the key of the escape sequence at the start of seq and returns its length. If seq does not
+// start with the sequence of a known key, 0 is returned instead. The sequence is incomplete if seq ends before its
+// final byte.
+func keySequence(seq []byte) (n int, key rune, complete bool) {
+	if len(seq) < 2 {
+		return 0, 0, true
+	}
+	if string(seq[:2]) != esc && string(seq[:2]) != ss3 {
+		return 0, 0, true
+	}
+
+	// the parameters are digits separated by semicolons, the first one being the key of the ~ sequences and the
+	// others the modifiers.
+	param, first := 0, true
+	for i := 2; i < len(seq); i++ {
+		c := seq[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if first {
+				param = param*10 + int(c-'0')
+			}
+			continue
+		case c == ';':
+			first = false
+			continue
+		}
+
+		switch {
+		case c == 'A':
+			key = readline.CharPrev
+		case c == 'B':
+			key = readline.CharNext
+		case c == 'C':
+			key = readline.CharForward
+		case c == 'D':
+			key = readline.CharBackward
+		case c == 'H', c == '~' && (param == 1 || param == 7):
+			key = KeyLineStart
+		case c == 'F', c == '~' && (param == 4 || param == 8):
+			key = KeyLineEnd
+		case c == '~' && param == 3:
+			key = KeyDelete
+		case c == '~' && param == 5:
+			key = KeyPageUp
+		case c == '~' && param == 6:
+			key = KeyPageDown
+		default:
+			return 0, 0, true
+		}
+		return i + 1, key, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,121 @@
+package promptui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chzyer/readline"
+)
+
+func TestKeyReader(t *testing.T) {
+	tcs := []struct {
+		name   string
+		chunks []string
+		exp    []rune
+	}{
+		{name: "runes", chunks: []string{"aé"}, exp: []rune{'a', 'é'}},
+		{name: "control keys", chunks: []string{"\x17\r"}, exp: []rune{KeyDeleteWord, KeyEnter}},
+		{name: "arrows", chunks: []string{"\x1b[A\x1b[B\x1b[C\x1b[D"},
+			exp: []rune{readline.CharPrev, readline.CharNext, readline.CharForward, readline.CharBackward}},
+		{name: "application arrows", chunks: []string{"\x1bOA\x1bOB"}, exp: []rune{readline.CharPrev, readline.CharNext}},
+		{name: "modified arrow", chunks: []string{"\x1b[1;5A"}, exp: []rune{readline.CharPrev}},
+		{name: "home and end", chunks: []string{"\x1b[H\x1bOF\x1b[1~\x1b[4~\x1b[7~\x1b[8~"},
+			exp: []rune{KeyLineStart, KeyLineEnd, KeyLineStart, KeyLineEnd, KeyLineStart, KeyLineEnd}},
+		{name: "delete", chunks: []string{"\x1b[3~"}, exp: []rune{KeyDelete}},
+		{name: "pages", chunks: []string{"\x1b[5~\x1b[6~"}, exp: []rune{KeyPageUp, KeyPageDown}},
+		{name: "unknown sequence", chunks: []string{"\x1b[Z"}, exp: []rune{'\x1b', '[', 'Z'}},
+		{name: "lone escape", chunks: []string{"\x1b", "a"}, exp: []rune{'\x1b', 'a'}},
+		{name: "split sequence", chunks: []string{"\x1b[", "6~"}, exp: []rune{KeyPageDown}},
+		{name: "split rune", chunks: []string{"\xc3", "\xa9"}, exp: []rune{'é'}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewKeyReader(&chunkReader{chunks: tc.chunks})
+
+			var got []rune
+			for {
+				key, err := r.ReadKey()
+				if err != nil {
+					break
+				}
+				got = append(got, key)
+			}
+
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestKeyReaderRead(t *testing.T) {
+	r := NewKeyReader(&chunkReader{chunks: []string{"a\x1b[B", "\x1b"}})
+
+	b := make([]byte, 16)
+	n, err := r.Read(b)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if exp := "a" + string(rune(readline.CharNext)); string(b[:n]) != exp {
+		t.Errorf("Expected %q, got %q", exp, b[:n])
+	}
+
+	n, err = r.Read(b)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(b[:n]) != "\x1b" {
+		t.Errorf("Expected a lone escape, got %q", b[:n])
+	}
+}
+
+func TestSelectPageKeys(t *testing.T) {
+	term := NewTestTerminal("\x1b[6~", "\x1bOB", "\r")
+	s := Select{
+		Items:    []string{"a", "b", "c", "d", "e", "f"},
+		Size:     2,
+		Terminal: term,
+		Stdin:    term,
+		Stdout:   term,
+	}
+
+	index, _, err := s.Run()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if index != 3 {
+		t.Errorf("Expected index %d, got %d", 3, index)
+	}
+}
+
+func TestKeyReaderClear(t *testing.T) {
+	paste := newPasteReader(&chunkReader{chunks: []string{"\x1b[200~sec", "ret\x1b[201~\x1b[", "B\x1b"}}, false)
+	k := NewKeyReader(paste)
+
+	var keys []rune
+	for i := 0; i < 6; i++ {
+		r, err := k.ReadKey()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		keys = append(keys, r)
+	}
+	if string(keys) != "secret" {
+		t.Fatalf("Expected %q, got %q", "secret", string(keys))
+	}
+	k.clear()
+	paste.clear()
+
+	for name, buf := range map[string][]byte{"key reader": k.buf, "paste reader": paste.buf} {
+		for _, b := range buf {
+			if b != 0 {
+				t.Errorf("Expected the buffer of the %s to be cleared, got %q", name, buf)
+				break
+			}
+		}
+	}
+	if len(k.keys) != 0 || len(k.pending) != 0 || len(paste.out) != 0 {
+		t.Errorf("Expected the keys not read to be dropped, got %q and %q", k.keys, paste.out)
+	}
+}
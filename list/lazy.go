@@ -0,0 +1,81 @@
+package list
+
+import "sync"
+
+// Fetcher is a function signature used by a LazyList to load its items. It returns at most limit items
+// starting at the given offset, and whether more items are available after them.
+type Fetcher func(offset, limit int) ([]interface{}, bool, error)
+
+// LazyList is a List whose items are loaded by pages as the cursor gets close to the last loaded item, for
+// items coming from a remote source. The pages are loaded in the background so the list can still be moved
+// while loading.
+//
+// The LazyList must be locked while using it since a page can be added to it at any time. A Snapshot taken with the
+// lock held can be rendered without it.
+type LazyList struct {
+	*List
+	sync.Mutex
+
+	fetch   Fetcher
+	limit   int
+	more    bool
+	loading bool
+	err     error
+}
+
+// NewLazy creates a LazyList with a N number of visible items, loading limit items at a time with fetch. The
+// first page is loaded before returning and its error, if any, is returned.
+func NewLazy(fetch Fetcher, size, limit int) (*LazyList, error) {
+	items, more, err := fetch(0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := New(items, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LazyList{List: l, fetch: fetch, limit: limit, more: more}, nil
+}
+
+// Load starts loading the next page in the background if the cursor is less than a page away from the last
+// loaded item and more items are available. Once the page is added to the list, done is called without the
+// lock held. It returns whether a page is being loaded.
+func (l *LazyList) Load(done func()) bool {
+	if l.loading {
+		return true
+	}
+
+	if !l.more || l.err != nil || l.cursor+l.size < len(l.scope) {
+		return false
+	}
+
+	l.loading = true
+	offset := len(l.items)
+
+	go func() {
+		items, more, err := l.fetch(offset, l.limit)
+
+		l.Lock()
+		l.Append(items...)
+		l.more = more
+		l.err = err
+		l.loading = false
+		l.Unlock()
+
+		done()
+	}()
+
+	return true
+}
+
+// Loading reports whether a page is being loaded.
+func (l *LazyList) Loading() bool {
+	return l.loading
+}
+
+// Err returns the error returned when loading the last page, if any. No other page is loaded after an error.
+func (l *LazyList) Err() error {
+	return l.err
+}
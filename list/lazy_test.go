@@ -0,0 +1,92 @@
+package list
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestListAppend(t *testing.T) {
+	l, err := New([]string{"apple", "banana"}, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return l.Item(idx).(string)[0] == input[0]
+	}
+
+	l.Search("b")
+	l.Append("blueberry", "cherry")
+	items, _ := l.Items()
+	if !reflect.DeepEqual(items, []interface{}{"banana", "blueberry"}) {
+		t.Errorf("Expected only the matching items to be appended, got %v", items)
+	}
+
+	l.CancelSearch()
+	items, _ = l.Items()
+	if !reflect.DeepEqual(items, []interface{}{"apple", "banana", "blueberry", "cherry"}) {
+		t.Errorf("Expected all the items, got %v", items)
+	}
+}
+
+func TestLazyList(t *testing.T) {
+	numbers := []interface{}{0, 1, 2, 3, 4, 5, 6}
+	failAt := -1
+	fetch := func(offset, limit int) ([]interface{}, bool, error) {
+		if offset == failAt {
+			return nil, true, errors.New("unavailable")
+		}
+
+		end := offset + limit
+		if end > len(numbers) {
+			end = len(numbers)
+		}
+		return numbers[offset:end], end < len(numbers), nil
+	}
+
+	l, err := NewLazy(fetch, 2, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if l.Len() != 3 {
+		t.Fatalf("Expected the first page of 3 items, got %d", l.Len())
+	}
+
+	done := make(chan struct{})
+	load := func() bool {
+		l.Lock()
+		defer l.Unlock()
+		return l.Load(func() { done <- struct{}{} })
+	}
+
+	if load() {
+		t.Errorf("Expected no page loaded far from the last item")
+	}
+
+	l.Next()
+	if !load() {
+		t.Fatalf("Expected the next page to be loaded")
+	}
+	<-done
+
+	if l.Len() != 6 || l.Loading() {
+		t.Errorf("Expected 6 items loaded, got %d", l.Len())
+	}
+
+	failAt = 6
+	l.SetCursor(5)
+	if !load() {
+		t.Fatalf("Expected the next page to be loaded")
+	}
+	<-done
+
+	if l.Err() == nil {
+		t.Errorf("Expected the error of the last page")
+	}
+
+	if load() {
+		t.Errorf("Expected no page loaded after an error")
+	}
+}
@@ -3,6 +3,7 @@ package list
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -11,6 +12,10 @@ import (
 // the item fits the searched term.
 type Searcher func(input string, index int) bool
 
+// Matcher is a function signature that can be used to highlight why the items match the searched term. It
+// returns the positions of the runes matching the term inside the label of the item at the given index.
+type Matcher func(term string, index int) []int
+
 // NotFound is an index returned when no item was selected. This could
 // happen due to a search without results.
 const NotFound = -1
@@ -18,13 +23,29 @@ const NotFound = -1
 // List holds a collection of items that can be displayed with an N number of
 // visible items. The list can be moved up, down by one item of time or an
 // entire page (ie: visible size). It keeps track of the current selected item.
+//
+// A List is not safe for concurrent use: its methods must be called from a single goroutine, or with a lock
+// held by all the goroutines using it, like the lock of a LazyList. The Searcher, the scorer, the Matcher and
+// Disabled are called with the lock held, so they can call the methods of the list too. A goroutine rendering
+// the list without the lock reads a Snapshot of it instead.
 type List struct {
-	items    []*interface{}
-	scope    []*interface{}
-	cursor   int // cursor holds the index of the current selected item
-	size     int // size is the number of visible options
+	items    []interface{}
+	scope    []int // scope holds the indexes of the items matching the current search, in display order
+	cursor   int   // cursor holds the index of the current selected item
+	size     int   // size is the number of visible options
 	start    int
+	scorer   Scorer
+	matcher  Matcher
+	term     string // term is the current searched term
 	Searcher Searcher
+
+	// Wrap makes Next move from the last item to the first one and Prev from the first item to the last one,
+	// scrolling the visible items accordingly.
+	Wrap bool
+
+	// Disabled is an optional function reporting whether the item at the given index cannot be selected.
+	// Disabled items are still listed, but the cursor skips over them.
+	Disabled func(index int) bool
 }
 
 // New creates and initializes a list of searchable items. The items attribute must be a slice type with a
@@ -39,27 +60,177 @@ func New(items interface{}, size int) (*List, error) {
 	}
 
 	slice := reflect.ValueOf(items)
-	values := make([]*interface{}, slice.Len())
+	values := make([]interface{}, slice.Len())
+	scope := make([]int, slice.Len())
 
 	for i := range values {
-		item := slice.Index(i).Interface()
-		values[i] = &item
+		values[i] = slice.Index(i).Interface()
+		scope[i] = i
+	}
+
+	return &List{size: size, items: values, scope: scope}, nil
+}
+
+// Append adds items at the end of the list. If a term is searched, only the new items matching it are added to
+// the searched list.
+func (l *List) Append(items ...interface{}) {
+	first := len(l.items)
+	l.items = append(l.items, items...)
+
+	switch {
+	case l.term == "":
+		for i := first; i < len(l.items); i++ {
+			l.scope = append(l.scope, i)
+		}
+	case l.scorer != nil:
+		l.scope = l.score(l.term)
+	default:
+		for i := first; i < len(l.items); i++ {
+			if l.Searcher(l.term, i) {
+				l.scope = append(l.scope, i)
+			}
+		}
+	}
+}
+
+// Add adds an item at the end of the list like Append. If a term is searched, it is only listed if it matches.
+func (l *List) Add(item interface{}) {
+	l.Append(item)
+}
+
+// RemoveAt removes the item at the given index inside the original items, shifting the indexes of the
+// following items down by one. The searched term, if any, is searched again and the cursor stays on the item
+// it was on, or moves to the next one if it was on the removed item. Indexes out of range are ignored.
+//
+// The Searcher, the scorer and the Matcher are called with the new indexes, so they must look the items up in
+// the list, with Item, or in a slice updated the same way.
+func (l *List) RemoveAt(index int) {
+	if index < 0 || index >= len(l.items) {
+		return
+	}
+
+	current := l.Index()
+	l.items = append(l.items[:index], l.items[index+1:]...)
+
+	switch {
+	case current == index:
+		current = NotFound
+	case current > index:
+		current--
+	}
+	l.refresh(current, l.cursor)
+}
+
+// MoveToFront moves the item at the given index to the front of the list, shifting the items before it up by
+// one, as done to list the most recently used items first. Like with RemoveAt, the searched term is searched
+// again and the cursor stays on the item it was on. Indexes out of range are ignored.
+func (l *List) MoveToFront(index int) {
+	if index <= 0 || index >= len(l.items) {
+		return
+	}
+
+	current := l.Index()
+	item := l.items[index]
+	copy(l.items[1:index+1], l.items[:index])
+	l.items[0] = item
+
+	switch {
+	case current == index:
+		current = 0
+	case current != NotFound && current < index:
+		current++
+	}
+	l.refresh(current, l.cursor)
+}
+
+// Set replaces all the items of the list. The searched term, if any, is searched again among the new items and
+// the cursor keeps its position, clamped to the end of the list. Like with RemoveAt, the Searcher, the scorer
+// and the Matcher are called with the indexes of the new items.
+func (l *List) Set(items []interface{}) {
+	l.items = append([]interface{}(nil), items...)
+	l.refresh(NotFound, l.cursor)
+}
+
+// position returns the position inside the searched list of the item at the given index, or NotFound if it does
+// not match the searched term.
+func (l *List) position(index int) int {
+	for pos, i := range l.scope {
+		if i == index {
+			return pos
+		}
+	}
+	return NotFound
+}
+
+// refresh searches the current term again after the items changed, then places the cursor on the item at the
+// given index if it is still listed, or else at the given position of the searched list, clamped into range.
+func (l *List) refresh(index, pos int) {
+	if l.term == "" {
+		l.scope = make([]int, len(l.items))
+		for i := range l.scope {
+			l.scope[i] = i
+		}
+	} else {
+		l.search(l.term)
+	}
+
+	if p := l.position(index); index != NotFound && p != NotFound {
+		pos = p
 	}
 
-	return &List{size: size, items: values, scope: values}, nil
+	if pos >= len(l.scope) {
+		pos = len(l.scope) - 1
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	l.cursor = pos
+
+	if max := len(l.scope) - l.size; l.start > max {
+		l.start = max
+	}
+	if l.start < 0 {
+		l.start = 0
+	}
+	l.scroll()
+	l.skipDisabled(true)
+}
+
+// SetMatcher sets the function returning the positions of the runes matching the searched term in each item,
+// as returned by Matches.
+func (l *List) SetMatcher(fn Matcher) {
+	l.matcher = fn
+}
+
+// SetScorer sets a scoring function used instead of the Searcher when searching the list. The items for which
+// it reports a match are displayed by descending score, the items with the same score keeping their original
+// order. See FuzzyScore for a fuzzy subsequence scorer.
+func (l *List) SetScorer(fn Scorer) {
+	l.scorer = fn
 }
 
 // Prev moves the visible list back one item. If the selected item is out of
 // view, the new select item becomes the last visible item. If the list is
-// already at the top, nothing happens.
+// already at the top, nothing happens unless Wrap is set.
 func (l *List) Prev() {
-	if l.cursor > 0 {
-		l.cursor--
+	for i := l.cursor - 1; i >= 0; i-- {
+		if !l.disabled(i) {
+			l.cursor = i
+			l.scroll()
+			return
+		}
 	}
 
-	if l.start > l.cursor {
-		l.start = l.cursor
+	if l.Wrap {
+		for i := len(l.scope) - 1; i > l.cursor; i-- {
+			if !l.disabled(i) {
+				l.cursor = i
+				break
+			}
+		}
 	}
+
+	l.scroll()
 }
 
 // Search allows the list to be filtered by a given term. The list must
@@ -68,7 +239,9 @@ func (l *List) Search(term string) {
 	term = strings.Trim(term, " ")
 	l.cursor = 0
 	l.start = 0
+	l.term = term
 	l.search(term)
+	l.skipDisabled(true)
 }
 
 // CancelSearch stops the current search and returns the list to its
@@ -76,21 +249,63 @@ func (l *List) Search(term string) {
 func (l *List) CancelSearch() {
 	l.cursor = 0
 	l.start = 0
-	l.scope = l.items
+	l.term = ""
+	l.scope = make([]int, len(l.items))
+	for i := range l.scope {
+		l.scope[i] = i
+	}
+	l.skipDisabled(true)
+}
+
+// ClearSearch stops the current search like CancelSearch, but keeps the cursor on the item it was on inside the
+// searched list.
+func (l *List) ClearSearch() {
+	current := l.Index()
+	l.CancelSearch()
+	if current == NotFound {
+		return
+	}
+
+	l.cursor = current
+	l.scroll()
 }
 
 func (l *List) search(term string) {
-	var scope []*interface{}
+	if l.scorer != nil {
+		l.scope = l.score(term)
+		return
+	}
+
+	var scope []int
 
-	for i, item := range l.items {
+	for i := range l.items {
 		if l.Searcher(term, i) {
-			scope = append(scope, item)
+			scope = append(scope, i)
 		}
 	}
 
 	l.scope = scope
 }
 
+// score returns the indexes of the items matched by the scorer, sorted by descending score.
+func (l *List) score(term string) []int {
+	var scope []int
+	scores := map[int]int{}
+
+	for i := range l.items {
+		if score, ok := l.scorer(term, i); ok {
+			scope = append(scope, i)
+			scores[i] = score
+		}
+	}
+
+	sort.SliceStable(scope, func(i, j int) bool {
+		return scores[scope[i]] > scores[scope[j]]
+	})
+
+	return scope
+}
+
 // Start returns the current render start position of the list.
 func (l *List) Start() int {
 	return l.start
@@ -109,6 +324,16 @@ func (l *List) SetStart(i int) {
 	}
 }
 
+// SetSize sets the number of visible items, scrolling the visible items so the
+// cursor stays in view. Values lower than 1 are set to 1.
+func (l *List) SetSize(size int) {
+	if size < 1 {
+		size = 1
+	}
+	l.size = size
+	l.scroll()
+}
+
 // SetCursor sets the position of the cursor in the list. Values out of bounds
 // will be clamped.
 func (l *List) SetCursor(i int) {
@@ -120,7 +345,12 @@ func (l *List) SetCursor(i int) {
 		i = 0
 	}
 	l.cursor = i
+	l.scroll()
+	l.skipDisabled(true)
+}
 
+// scroll moves the visible items so the cursor stays in view.
+func (l *List) scroll() {
 	if l.start > l.cursor {
 		l.start = l.cursor
 	} else if l.start+l.size <= l.cursor {
@@ -128,19 +358,111 @@ func (l *List) SetCursor(i int) {
 	}
 }
 
+// disabled reports whether the item at the given position of the searched list is disabled.
+func (l *List) disabled(pos int) bool {
+	return l.Disabled != nil && l.Disabled(l.index(pos))
+}
+
+// skipDisabled moves the cursor from a disabled item to the closest enabled item in the given direction, or
+// in the other direction if there is none. The cursor does not move if all the items are disabled.
+func (l *List) skipDisabled(forward bool) {
+	if len(l.scope) == 0 || !l.disabled(l.cursor) {
+		return
+	}
+
+	step := 1
+	if !forward {
+		step = -1
+	}
+
+	for _, s := range []int{step, -step} {
+		for i := l.cursor + s; i >= 0 && i < len(l.scope); i += s {
+			if !l.disabled(i) {
+				l.cursor = i
+				l.scroll()
+				return
+			}
+		}
+	}
+}
+
 // Next moves the visible list forward one item. If the selected item is out of
 // view, the new select item becomes the first visible item. If the list is
-// already at the bottom, nothing happens.
+// already at the bottom, nothing happens unless Wrap is set.
 func (l *List) Next() {
 	max := len(l.scope) - 1
 
-	if l.cursor < max {
-		l.cursor++
+	for i := l.cursor + 1; i <= max; i++ {
+		if !l.disabled(i) {
+			l.cursor = i
+			l.scroll()
+			return
+		}
 	}
 
-	if l.start+l.size <= l.cursor {
-		l.start = l.cursor - l.size + 1
+	if l.Wrap {
+		for i := 0; i < l.cursor; i++ {
+			if !l.disabled(i) {
+				l.cursor = i
+				break
+			}
+		}
 	}
+
+	l.scroll()
+}
+
+// Jump moves the cursor to the next enabled item of the searched list for which match returns true, wrapping around
+// to the top of the list. The current item is tried first when includeCurrent is true, otherwise it is tried
+// last. If no item matches, the cursor does not move and false is returned.
+func (l *List) Jump(match func(item interface{}) bool, includeCurrent bool) bool {
+	first := 1
+	if includeCurrent {
+		first = 0
+	}
+
+	for i := first; i < len(l.scope)+first; i++ {
+		j := (l.cursor + i) % len(l.scope)
+		if !l.disabled(j) && match(l.items[l.scope[j]]) {
+			l.SetCursor(j)
+			return true
+		}
+	}
+
+	return false
+}
+
+// JumpMatch moves the cursor to the next enabled item matching term, as matched by Search, without filtering the
+// list. The search wraps around the end of the list, or around its start when backward is set to move to the
+// previous matching item instead. If no other item matches, the cursor does not move and false is returned.
+func (l *List) JumpMatch(term string, backward bool) bool {
+	term = strings.Trim(term, " ")
+	if term == "" {
+		return false
+	}
+
+	n := len(l.scope)
+	for i := 1; i < n; i++ {
+		j := (l.cursor + i) % n
+		if backward {
+			j = (l.cursor - i + n) % n
+		}
+		if !l.disabled(j) && l.matches(term, l.scope[j]) {
+			l.SetCursor(j)
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches reports whether the item at the given index matches term, with the scorer or the Searcher.
+func (l *List) matches(term string, index int) bool {
+	if l.scorer != nil {
+		_, ok := l.scorer(term, index)
+		return ok
+	}
+	return l.Searcher != nil && l.Searcher(term, index)
 }
 
 // PageUp moves the visible list backward by x items. Where x is the size of the
@@ -160,6 +482,8 @@ func (l *List) PageUp() {
 	if cursor < l.cursor {
 		l.cursor = cursor
 	}
+
+	l.skipDisabled(false)
 }
 
 // PageDown moves the visible list forward by x items. Where x is the size of
@@ -185,6 +509,8 @@ func (l *List) PageDown() {
 	} else if cursor > l.cursor {
 		l.cursor = cursor
 	}
+
+	l.skipDisabled(true)
 }
 
 // CanPageDown returns whether a list can still PageDown().
@@ -201,15 +527,148 @@ func (l *List) CanPageUp() bool {
 // Index returns the index of the item currently selected inside the searched list. If no item is selected,
 // the NotFound (-1) index is returned.
 func (l *List) Index() int {
-	selected := l.scope[l.cursor]
+	return l.index(l.cursor)
+}
+
+// Item returns the item at the given index inside the original items.
+func (l *List) Item(index int) interface{} {
+	return l.items[index]
+}
+
+// Len returns the total number of items of the list.
+func (l *List) Len() int {
+	return len(l.items)
+}
+
+// MatchedLen returns the number of items matching the current search, or the total number of items if there
+// is no search.
+func (l *List) MatchedLen() int {
+	return len(l.scope)
+}
+
+// Matched returns the indexes of the items matching the current search, in the order they are displayed.
+func (l *List) Matched() []int {
+	matched := make([]int, len(l.scope))
+	copy(matched, l.scope)
+	return matched
+}
+
+// IsDisabled reports whether the item currently selected inside the searched list is disabled.
+func (l *List) IsDisabled() bool {
+	return len(l.scope) > 0 && l.disabled(l.cursor)
+}
+
+// index returns the index inside the original items of the item at the given position of the searched list.
+func (l *List) index(pos int) int {
+	if pos < 0 || pos >= len(l.scope) {
+		return NotFound
+	}
+	return l.scope[pos]
+}
+
+// ItemIndex returns the index inside the original items of the item at the given position of the slice
+// returned by Items. If the position is out of the visible items, the NotFound (-1) index is returned.
+func (l *List) ItemIndex(visible int) int {
+	i := l.start + visible
+	if visible < 0 || visible >= l.size || i >= len(l.scope) {
+		return NotFound
+	}
+
+	return l.index(i)
+}
+
+// Matches returns the positions of the runes matching the searched term for each of the items returned by
+// Items, in the same order. It returns nil if no term is searched or no Matcher is set.
+func (l *List) Matches() [][]int {
+	if l.matcher == nil || l.term == "" {
+		return nil
+	}
+
+	items, _ := l.Items()
+	matches := make([][]int, len(items))
+	for i := range items {
+		matches[i] = l.matcher(l.term, l.index(l.start+i))
+	}
+	return matches
+}
+
+// MatchRanges returns the runs of runes matching the searched term for each of the items returned by Items, in
+// the same order, as pairs of a start position and a length. A substring match is a single pair, while the
+// positions matched by a fuzzy Matcher can make several. It returns nil whenever Matches does.
+func (l *List) MatchRanges() [][]int {
+	matches := l.Matches()
+	if matches == nil {
+		return nil
+	}
+
+	ranges := make([][]int, len(matches))
+	for i, positions := range matches {
+		ranges[i] = runs(positions)
+	}
+	return ranges
+}
 
-	for i, item := range l.items {
-		if item == selected {
-			return i
+// runs returns the runs of consecutive positions as pairs of a start position and a length.
+func runs(positions []int) []int {
+	sorted := append([]int(nil), positions...)
+	sort.Ints(sorted)
+
+	var pairs []int
+	for i, p := range sorted {
+		switch {
+		case i > 0 && p == sorted[i-1]:
+		case i > 0 && p == sorted[i-1]+1:
+			pairs[len(pairs)-1]++
+		default:
+			pairs = append(pairs, p, 1)
 		}
 	}
+	return pairs
+}
 
-	return NotFound
+// Snapshot is a view of the visible items of a List at the time it was taken with List.Snapshot. It holds its own
+// copies, so it can be read without a lock while the list keeps changing.
+type Snapshot struct {
+	// Items are the visible items and Active the position of the cursor among them, as returned by Items.
+	Items  []interface{}
+	Active int
+
+	// Indexes are the indexes inside the original items of each of the Items, and Matches the positions of the
+	// runes matching the searched term inside each of them, as returned by Matches.
+	Indexes []int
+	Matches [][]int
+
+	// Start is the position of the first visible item inside the searched list, among Matched items out of
+	// Len items.
+	Start   int
+	Matched int
+	Len     int
+
+	// CanPageUp and CanPageDown report whether the list can still page up and down.
+	CanPageUp   bool
+	CanPageDown bool
+}
+
+// Snapshot returns a view of the visible items of the list, which is not changed by the later updates of the
+// list. Like the other methods, it must be called with the lock of the list held, if any.
+func (l *List) Snapshot() Snapshot {
+	items, active := l.Items()
+	indexes := make([]int, len(items))
+	for i := range items {
+		indexes[i] = l.index(l.start + i)
+	}
+
+	return Snapshot{
+		Items:       items,
+		Active:      active,
+		Indexes:     indexes,
+		Matches:     l.Matches(),
+		Start:       l.start,
+		Matched:     len(l.scope),
+		Len:         len(l.items),
+		CanPageUp:   l.CanPageUp(),
+		CanPageDown: l.CanPageDown(),
+	}
 }
 
 // Items returns a slice equal to the size of the list with the current visible
@@ -230,7 +689,7 @@ func (l *List) Items() ([]interface{}, int) {
 			active = j
 		}
 
-		result = append(result, *l.scope[i])
+		result = append(result, l.items[l.scope[i]])
 	}
 
 	return result, active
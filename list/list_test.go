@@ -3,6 +3,8 @@ package list
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -162,3 +164,569 @@ func castList(list []interface{}) []rune {
 	}
 	return result
 }
+
+func TestListItemIndex(t *testing.T) {
+	letters := []string{"a", "b", "c", "d", "e"}
+
+	l, err := New(letters, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return letters[idx] != "b"
+	}
+
+	l.Search("x")
+	l.Next()
+	l.Next()
+
+	if got := l.ItemIndex(0); got != 2 {
+		t.Errorf("Expected index 2 for the first visible item, got %d", got)
+	}
+
+	if got := l.ItemIndex(1); got != 3 {
+		t.Errorf("Expected index 3 for the second visible item, got %d", got)
+	}
+
+	if got := l.ItemIndex(2); got != NotFound {
+		t.Errorf("Expected NotFound out of the visible items, got %d", got)
+	}
+}
+
+func TestListJump(t *testing.T) {
+	words := []string{"apple", "banana", "blueberry", "cherry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	startsWith := func(prefix string) func(item interface{}) bool {
+		return func(item interface{}) bool {
+			return item.(string)[:len(prefix)] == prefix
+		}
+	}
+
+	if !l.Jump(startsWith("b"), false) || l.Index() != 1 {
+		t.Errorf("Expected cursor at 1, got %d", l.Index())
+	}
+
+	if !l.Jump(startsWith("b"), true) || l.Index() != 1 {
+		t.Errorf("Expected cursor to stay at 1, got %d", l.Index())
+	}
+
+	if !l.Jump(startsWith("b"), false) || l.Index() != 2 {
+		t.Errorf("Expected cursor at 2, got %d", l.Index())
+	}
+
+	if l.Start() != 1 {
+		t.Errorf("Expected the list to scroll to 1, got %d", l.Start())
+	}
+
+	if !l.Jump(startsWith("a"), false) || l.Index() != 0 {
+		t.Errorf("Expected cursor to wrap around to 0, got %d", l.Index())
+	}
+
+	if l.Jump(startsWith("z"), false) || l.Index() != 0 {
+		t.Errorf("Expected cursor to stay at 0 without a match, got %d", l.Index())
+	}
+}
+
+func TestListDisabled(t *testing.T) {
+	letters := []string{"a", "b", "c", "d", "e", "f"}
+	disabled := map[int]bool{0: true, 2: true, 3: true, 5: true}
+
+	newList := func() *List {
+		l, err := New(letters, 3)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		l.Disabled = func(idx int) bool { return disabled[idx] }
+		return l
+	}
+
+	t.Run("when moving through the list", func(t *testing.T) {
+		l := newList()
+		l.SetCursor(0)
+		if l.Index() != 1 {
+			t.Errorf("Expected cursor to skip to 1, got %d", l.Index())
+		}
+
+		l.Next()
+		if l.Index() != 4 {
+			t.Errorf("Expected cursor to skip to 4, got %d", l.Index())
+		}
+
+		l.Next()
+		if l.Index() != 4 {
+			t.Errorf("Expected cursor to stay at 4, got %d", l.Index())
+		}
+
+		l.Prev()
+		if l.Index() != 1 {
+			t.Errorf("Expected cursor to skip back to 1, got %d", l.Index())
+		}
+
+		l.Prev()
+		if l.Index() != 1 {
+			t.Errorf("Expected cursor to stay at 1, got %d", l.Index())
+		}
+	})
+
+	t.Run("when paging", func(t *testing.T) {
+		l := newList()
+		l.SetCursor(0)
+
+		l.PageDown()
+		if l.Index() != 4 {
+			t.Errorf("Expected cursor at 4, got %d", l.Index())
+		}
+
+		l.PageUp()
+		if l.Index() != 1 {
+			t.Errorf("Expected cursor at 1, got %d", l.Index())
+		}
+	})
+
+	t.Run("when searching", func(t *testing.T) {
+		l := newList()
+		l.Searcher = func(input string, idx int) bool {
+			return idx >= 3
+		}
+
+		l.Search("x")
+		items, _ := l.Items()
+		if !reflect.DeepEqual(items, []interface{}{"d", "e", "f"}) {
+			t.Errorf("Expected disabled items to be listed, got %v", items)
+		}
+
+		if l.Index() != 4 {
+			t.Errorf("Expected cursor at 4, got %d", l.Index())
+		}
+	})
+}
+
+func TestListMatches(t *testing.T) {
+	words := []string{"apple", "banana", "cherry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return strings.Contains(words[idx], input)
+	}
+	l.SetMatcher(func(input string, idx int) []int {
+		i := strings.Index(words[idx], input)
+		return []int{i, i + 1}
+	})
+
+	if m := l.Matches(); m != nil {
+		t.Errorf("Expected no matches without search, got %v", m)
+	}
+
+	l.Search("an")
+	exp := [][]int{{1, 2}}
+	if m := l.Matches(); !reflect.DeepEqual(m, exp) {
+		t.Errorf("Expected %v, got %v", exp, m)
+	}
+
+	l.Search("e")
+	l.Next()
+	exp = [][]int{{4, 5}, {2, 3}}
+	if m := l.Matches(); !reflect.DeepEqual(m, exp) {
+		t.Errorf("Expected %v, got %v", exp, m)
+	}
+}
+
+func TestListMatchRanges(t *testing.T) {
+	words := []string{"banana", "cherry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return strings.ContainsAny(words[idx], input)
+	}
+	l.SetMatcher(func(input string, idx int) []int {
+		var positions []int
+		for i, r := range words[idx] {
+			if strings.ContainsRune(input, r) {
+				positions = append(positions, i)
+			}
+		}
+		return positions
+	})
+
+	if r := l.MatchRanges(); r != nil {
+		t.Errorf("Expected no ranges without search, got %v", r)
+	}
+
+	l.Search("an")
+	exp := [][]int{{1, 5}}
+	if r := l.MatchRanges(); !reflect.DeepEqual(r, exp) {
+		t.Errorf("Expected %v, got %v", exp, r)
+	}
+
+	l.Search("cr")
+	exp = [][]int{{0, 1, 3, 2}}
+	if r := l.MatchRanges(); !reflect.DeepEqual(r, exp) {
+		t.Errorf("Expected %v, got %v", exp, r)
+	}
+
+	if pairs := runs([]int{4, 1, 2, 2}); !reflect.DeepEqual(pairs, []int{1, 2, 4, 1}) {
+		t.Errorf("Expected unsorted and repeated positions to be merged, got %v", pairs)
+	}
+}
+
+func TestListWrap(t *testing.T) {
+	letters := []string{"a", "b", "c", "d", "e"}
+
+	l, err := New(letters, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	l.Wrap = true
+
+	l.Prev()
+	if l.Index() != 4 || l.Start() != 3 {
+		t.Errorf("Expected cursor at 4 starting at 3, got %d starting at %d", l.Index(), l.Start())
+	}
+
+	if l.CanPageDown() || !l.CanPageUp() {
+		t.Errorf("Expected to only be able to page up")
+	}
+
+	l.Next()
+	if l.Index() != 0 || l.Start() != 0 {
+		t.Errorf("Expected cursor at 0 starting at 0, got %d starting at %d", l.Index(), l.Start())
+	}
+
+	l.Disabled = func(idx int) bool { return idx == 0 }
+	l.SetCursor(1)
+	l.Prev()
+	if l.Index() != 4 {
+		t.Errorf("Expected cursor to wrap to 4 skipping disabled items, got %d", l.Index())
+	}
+}
+
+func TestListLen(t *testing.T) {
+	words := []string{"apple", "banana", "cherry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return strings.Contains(words[idx], input)
+	}
+
+	if l.Len() != 3 || l.MatchedLen() != 3 {
+		t.Errorf("Expected 3 items and 3 matched, got %d and %d", l.Len(), l.MatchedLen())
+	}
+
+	l.Search("e")
+	if l.Len() != 3 || l.MatchedLen() != 2 {
+		t.Errorf("Expected 3 items and 2 matched, got %d and %d", l.Len(), l.MatchedLen())
+	}
+
+	if m := l.Matched(); !reflect.DeepEqual(m, []int{0, 2}) {
+		t.Errorf("Expected matched indexes [0 2], got %v", m)
+	}
+}
+
+func TestListClearSearch(t *testing.T) {
+	words := []string{"apple", "banana", "cherry", "date", "elderberry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return strings.Contains(words[idx], input)
+	}
+
+	l.Search("e")
+	l.Next()
+	l.Next()
+	if l.Index() != 3 {
+		t.Fatalf("Expected cursor on date, got %d", l.Index())
+	}
+
+	l.ClearSearch()
+	if l.Index() != 3 || l.Start() != 2 {
+		t.Errorf("Expected cursor at 3 starting at 2, got %d starting at %d", l.Index(), l.Start())
+	}
+
+	if l.MatchedLen() != 5 {
+		t.Errorf("Expected all items after clearing the search, got %d", l.MatchedLen())
+	}
+
+	l.Search("z")
+	l.ClearSearch()
+	if l.Index() != 0 {
+		t.Errorf("Expected cursor at 0 when no item matched, got %d", l.Index())
+	}
+}
+
+func TestListJumpMatch(t *testing.T) {
+	words := []string{"apple", "banana", "cherry", "date", "elderberry"}
+
+	l, err := New(words, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.Searcher = func(input string, idx int) bool {
+		return strings.Contains(words[idx], input)
+	}
+
+	tcs := []struct {
+		term     string
+		backward bool
+		expected int
+		ok       bool
+	}{
+		{"e", false, 2, true},
+		{"e", false, 3, true},
+		{"e", false, 4, true},
+		{"e", false, 0, true},
+		{"e", true, 4, true},
+		{"an", true, 1, true},
+		{"an", false, 1, false},
+		{"z", false, 1, false},
+	}
+
+	for _, tc := range tcs {
+		ok := l.JumpMatch(tc.term, tc.backward)
+		if ok != tc.ok || l.Index() != tc.expected {
+			t.Errorf("Expected jumping to %q to end at %d (%v), got %d (%v)", tc.term, tc.expected, tc.ok, l.Index(), ok)
+		}
+	}
+
+	if l.MatchedLen() != len(words) {
+		t.Errorf("Expected the list not to be filtered, got %d items", l.MatchedLen())
+	}
+}
+
+func TestListSetSize(t *testing.T) {
+	letters := []string{"a", "b", "c", "d", "e"}
+
+	l, err := New(letters, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.SetCursor(4)
+	l.SetSize(2)
+	if items, idx := l.Items(); len(items) != 2 || idx != 1 || l.Start() != 3 {
+		t.Errorf("Expected 2 items with the cursor on the last one, got %v at %d starting at %d", items, idx, l.Start())
+	}
+
+	l.SetSize(0)
+	if items, _ := l.Items(); len(items) != 1 {
+		t.Errorf("Expected 1 visible item, got %v", items)
+	}
+}
+
+func TestListMutations(t *testing.T) {
+	var words []interface{}
+	for _, w := range []string{"apple", "banana", "cherry", "date", "elderberry"} {
+		words = append(words, w)
+	}
+
+	newList := func(t *testing.T) *List {
+		l, err := New(words, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		l.Searcher = func(input string, idx int) bool {
+			return strings.Contains(l.Item(idx).(string), input)
+		}
+		return l
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		l := newList(t)
+		l.Search("rr")
+		l.Add("strawberry")
+		l.Add("fig")
+		if l.Len() != 7 || l.MatchedLen() != 3 {
+			t.Errorf("Expected 7 items and 3 matched, got %d and %d", l.Len(), l.MatchedLen())
+		}
+	})
+
+	t.Run("RemoveAt before the cursor", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(3)
+		l.RemoveAt(1)
+		if l.Len() != 4 || l.Item(l.Index()) != "date" {
+			t.Errorf("Expected the cursor to stay on date, got %v", l.Item(l.Index()))
+		}
+	})
+
+	t.Run("RemoveAt under the cursor", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(1)
+		l.RemoveAt(1)
+		if l.Item(l.Index()) != "cherry" {
+			t.Errorf("Expected the cursor to move to cherry, got %v", l.Item(l.Index()))
+		}
+	})
+
+	t.Run("RemoveAt the last item", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(4)
+		l.RemoveAt(4)
+		if l.Index() != 3 || l.Start() != 2 {
+			t.Errorf("Expected the cursor at 3 starting at 2, got %d starting at %d", l.Index(), l.Start())
+		}
+	})
+
+	t.Run("RemoveAt with a search", func(t *testing.T) {
+		l := newList(t)
+		l.Search("e")
+		l.Next()
+		l.RemoveAt(0)
+		if l.MatchedLen() != 3 || l.Item(l.Index()) != "cherry" {
+			t.Errorf("Expected 3 matched with the cursor on cherry, got %d on %v", l.MatchedLen(), l.Item(l.Index()))
+		}
+
+		l.RemoveAt(10)
+		if l.Len() != 4 {
+			t.Errorf("Expected an index out of range to be ignored, got %d items", l.Len())
+		}
+	})
+
+	t.Run("MoveToFront under the cursor", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(3)
+		l.MoveToFront(3)
+		if l.Item(0) != "date" || l.Item(1) != "apple" || l.Index() != 0 {
+			t.Errorf("Expected date first with the cursor on it, got %v first and the cursor at %d", l.Item(0), l.Index())
+		}
+	})
+
+	t.Run("MoveToFront after the cursor", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(1)
+		l.MoveToFront(4)
+		if l.Item(0) != "elderberry" || l.Item(l.Index()) != "banana" {
+			t.Errorf("Expected the cursor to stay on banana, got %v", l.Item(l.Index()))
+		}
+	})
+
+	t.Run("MoveToFront before the cursor", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(4)
+		l.MoveToFront(2)
+		if l.Index() != 4 || l.Item(l.Index()) != "elderberry" || l.Item(3) != "date" {
+			t.Errorf("Expected the cursor to stay on elderberry, got %v", l.Item(l.Index()))
+		}
+	})
+
+	t.Run("MoveToFront with a search", func(t *testing.T) {
+		l := newList(t)
+		l.Search("e")
+		l.Next()
+		l.MoveToFront(4)
+		if l.MatchedLen() != 4 || l.Item(0) != "elderberry" || l.Item(l.Index()) != "cherry" {
+			t.Errorf("Expected 4 matched with the cursor on cherry, got %d on %v", l.MatchedLen(), l.Item(l.Index()))
+		}
+
+		l.MoveToFront(10)
+		if l.Item(0) != "elderberry" {
+			t.Errorf("Expected an index out of range to be ignored, got %v first", l.Item(0))
+		}
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		l := newList(t)
+		l.SetCursor(4)
+		l.Set([]interface{}{"kiwi", "lemon"})
+		if l.Len() != 2 || l.Index() != 1 || l.Start() != 0 {
+			t.Errorf("Expected the cursor clamped to 1 starting at 0, got %d starting at %d", l.Index(), l.Start())
+		}
+
+		l.Set(nil)
+		if items, idx := l.Items(); len(items) != 0 || idx != NotFound || l.Index() != NotFound {
+			t.Errorf("Expected an empty list, got %v at %d", items, idx)
+		}
+	})
+}
+
+func TestListSnapshot(t *testing.T) {
+	letters := []string{"a", "b", "c", "d", "e"}
+
+	l, err := New(letters, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.SetCursor(3)
+	snap := l.Snapshot()
+	l.Prev()
+	l.Set([]interface{}{"z"})
+
+	exp := Snapshot{
+		Items:       []interface{}{"c", "d"},
+		Active:      1,
+		Indexes:     []int{2, 3},
+		Start:       2,
+		Matched:     5,
+		Len:         5,
+		CanPageUp:   true,
+		CanPageDown: true,
+	}
+	if !reflect.DeepEqual(snap, exp) {
+		t.Errorf("Expected %+v, got %+v", exp, snap)
+	}
+}
+
+// TestListSnapshotConcurrent renders snapshots while items are appended in the background, for the race detector.
+func TestListSnapshotConcurrent(t *testing.T) {
+	l, err := New([]string{"a"}, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var mu sync.Mutex
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			mu.Lock()
+			l.Append(i)
+			mu.Unlock()
+		}
+	}()
+
+	for {
+		mu.Lock()
+		l.Next()
+		snap := l.Snapshot()
+		mu.Unlock()
+
+		for _, item := range snap.Items {
+			_ = fmt.Sprint(item)
+		}
+		if snap.Active == NotFound {
+			t.Fatalf("Expected the cursor to be visible, got %+v", snap)
+		}
+
+		select {
+		case <-done:
+			if l.Len() != 501 {
+				t.Errorf("Expected 501 items, got %d", l.Len())
+			}
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package list
+
+import "unicode"
+
+// Scorer is a function signature that can be used instead of a Searcher to rank the items matching a term.
+// It is called on each items of the list and returns the score of the item and whether it matches the term.
+// Items with a higher score are displayed first.
+type Scorer func(term string, index int) (score int, matched bool)
+
+// Bonuses added to the score of a fuzzy match.
+const (
+	consecutiveBonus = 4 // the rune follows the previous matched rune
+	wordStartBonus   = 2 // the rune starts a word
+)
+
+// FuzzyScore reports whether all the runes of term appear in str in the same order, like in fzf, ignoring the
+// case. Each matched rune scores a point, with bonuses for runes matched consecutively or at the start of a
+// word, so that the closest matches get a higher score.
+func FuzzyScore(term, str string) (int, bool) {
+	score, _, matched := FuzzyMatch(term, str)
+	return score, matched
+}
+
+// FuzzyMatch is like FuzzyScore and also returns the positions of the runes of str matching the runes of term.
+func FuzzyMatch(term, str string) (int, []int, bool) {
+	pattern := []rune(term)
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	score := 0
+	j := 0
+	prev := -2
+	var positions []int
+	runes := []rune(str)
+	for i, r := range runes {
+		if unicode.ToLower(r) != unicode.ToLower(pattern[j]) {
+			continue
+		}
+
+		score++
+		if prev == i-1 {
+			score += consecutiveBonus
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += wordStartBonus
+		}
+
+		prev = i
+		positions = append(positions, i)
+		j++
+		if j == len(pattern) {
+			return score, positions, true
+		}
+	}
+
+	return 0, nil, false
+}
@@ -0,0 +1,63 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tcs := []struct {
+		term    string
+		str     string
+		matched bool
+	}{
+		{term: "", str: "anything", matched: true},
+		{term: "bp", str: "Bell Pepper", matched: true},
+		{term: "BELL", str: "bell pepper", matched: true},
+		{term: "pb", str: "Bell Pepper", matched: false},
+		{term: "habaneros", str: "Habanero", matched: false},
+	}
+
+	for _, tc := range tcs {
+		if _, matched := FuzzyScore(tc.term, tc.str); matched != tc.matched {
+			t.Errorf("Expected %q matching %q to be %v, got %v", tc.term, tc.str, tc.matched, matched)
+		}
+	}
+
+	consecutive, _ := FuzzyScore("hab", "Habanero")
+	scattered, _ := FuzzyScore("hab", "Hot Asian Bean")
+	inside, _ := FuzzyScore("hab", "Cohabitation")
+	if consecutive <= inside || inside <= scattered {
+		t.Errorf("Expected consecutive %d > inside %d > scattered %d", consecutive, inside, scattered)
+	}
+}
+
+func TestListScorer(t *testing.T) {
+	words := []string{"cab", "abc", "xaxbxc", "abd", "bca"}
+
+	l, err := New(words, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l.SetScorer(func(term string, idx int) (int, bool) {
+		return FuzzyScore(term, words[idx])
+	})
+
+	l.Search("ab")
+	items, idx := l.Items()
+	exp := []interface{}{"abc", "abd", "cab", "xaxbxc"}
+	if !reflect.DeepEqual(items, exp) {
+		t.Errorf("Expected %v, got %v", exp, items)
+	}
+
+	if idx != 0 || l.Index() != 1 {
+		t.Errorf("Expected the cursor on abc at 1, got %d", l.Index())
+	}
+
+	l.CancelSearch()
+	items, _ = l.Items()
+	if !reflect.DeepEqual(items, []interface{}{"cab", "abc", "xaxbxc", "abd", "bca"}) {
+		t.Errorf("Expected the original order, got %v", items)
+	}
+}
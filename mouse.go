@@ -0,0 +1,217 @@
+package promptui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// enableMouse turns on the reporting of the mouse buttons and wheel with the SGR encoding, where each event is
+// sent as "\033[<button;column;rowM", or with a final m when a button is released. disableMouse turns it off.
+const (
+	enableMouse  = "\033[?1000h\033[?1006h"
+	disableMouse = "\033[?1006l\033[?1000l"
+)
+
+// queryCursor asks the terminal for the position of the cursor, which it replies with "\033[row;columnR".
+const queryCursor = "\033[6n"
+
+// keyMouse replaces each mouse event in the input of a select. The event itself is taken from the mouseReader.
+const keyMouse = '\uE04D'
+
+// doubleClickInterval is the maximum time between two clicks on the same item to select it.
+const doubleClickInterval = 500 * time.Millisecond
+
+type mouseAction int
+
+const (
+	mouseClick mouseAction = iota
+	mouseWheelUp
+	mouseWheelDown
+)
+
+// mouseEvent is a click or a wheel scroll at the given row of the terminal, counted from 1 for the top row.
+type mouseEvent struct {
+	action mouseAction
+	row    int
+}
+
+// mouseReader removes the mouse events and the replies to queryCursor from the input of a select. Each click and
+// wheel scroll is replaced by keyMouse, and kept to be taken with next. The other mouse events are dropped.
+//
+// The replies give the row of the cursor below the frame of the select, from which the clicked rows are mapped to
+// the lines of the frame.
+type mouseReader struct {
+	io.ReadCloser
+
+	mu      sync.Mutex
+	events  []mouseEvent
+	queries []int
+
+	// top is the row of the first line of the frame, known once the terminal replied to a query.
+	top   int
+	known bool
+
+	// pending holds an incomplete sequence at the end of the previous read, and out the filtered input that did
+	// not fit in the previous read.
+	pending []byte
+	out     []byte
+}
+
+func newMouseReader(r io.ReadCloser) *mouseReader {
+	return &mouseReader{ReadCloser: r}
+}
+
+func (r *mouseReader) Read(b []byte) (int, error) {
+	for len(r.out) == 0 {
+		buf := make([]byte, len(b))
+		n, err := r.ReadCloser.Read(buf)
+
+		data := append(r.pending, buf[:n]...)
+		r.pending = nil
+		if err != nil {
+			r.out = data
+		} else {
+			r.out, r.pending = r.filter(data)
+		}
+
+		if err != nil && len(r.out) == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(b, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// filter returns the data without the mouse events and the replies to queryCursor, and the incomplete sequence
+// left at its end.
+func (r *mouseReader) filter(data []byte) (out, rest []byte) {
+	for i := 0; i < len(data); {
+		if data[i] != '\033' {
+			out = append(out, data[i])
+			i++
+			continue
+		}
+
+		n, event, complete := r.sequence(data[i:])
+		switch {
+		case !complete:
+			return out, data[i:]
+		case n == 0:
+			out = append(out, data[i])
+			i++
+		default:
+			if event {
+				out = append(out, string(keyMouse)...)
+			}
+			i += n
+		}
+	}
+	return out, nil
+}
+
+// sequence handles the mouse event or the reply at the start of seq and returns its length, and whether an event
+// was kept. If seq does not start with one, 0 is returned instead. The sequence is incomplete if seq ends before
+// its final byte.
+func (r *mouseReader) sequence(seq []byte) (n int, event, complete bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mouse := strings.HasPrefix(string(seq), "\033[<")
+	reply := !mouse && len(r.queries) > 0 && strings.HasPrefix(string(seq), "\033[")
+	if !mouse && !reply {
+		return 0, false, true
+	}
+
+	start := 2
+	if mouse {
+		start = 3
+	}
+
+	for i := start; i < len(seq); i++ {
+		c := seq[i]
+		if c >= '0' && c <= '9' || c == ';' {
+			continue
+		}
+
+		params := strings.Split(string(seq[start:i]), ";")
+		switch {
+		case mouse && (c == 'M' || c == 'm') && len(params) == 3:
+			event = r.mouseEvent(params, c == 'M')
+		case reply && c == 'R' && len(params) == 2:
+			row, _ := strconv.Atoi(params[0])
+			r.top = row - r.queries[0]
+			r.known = true
+			r.queries = r.queries[1:]
+		default:
+			return 0, false, true
+		}
+		return i + 1, event, true
+	}
+	return 0, false, false
+}
+
+// mouseEvent keeps a click or a wheel scroll with the params of its sequence, being the button, the column and
+// the row, and reports whether the event was kept. It must be called with the lock held.
+func (r *mouseReader) mouseEvent(params []string, press bool) bool {
+	button, _ := strconv.Atoi(params[0])
+	row, _ := strconv.Atoi(params[2])
+
+	// the modifier keys held during the event are ignored, as are the moves and the releases of the buttons.
+	button &^= 4 | 8 | 16
+	if !press || button&32 != 0 {
+		return false
+	}
+
+	var action mouseAction
+	switch button {
+	case 0:
+		action = mouseClick
+	case 64:
+		action = mouseWheelUp
+	case 65:
+		action = mouseWheelDown
+	default:
+		return false
+	}
+
+	r.events = append(r.events, mouseEvent{action: action, row: row})
+	return true
+}
+
+// next takes the oldest event replaced by keyMouse.
+func (r *mouseReader) next() (mouseEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == 0 {
+		return mouseEvent{}, false
+	}
+	ev := r.events[0]
+	r.events = r.events[1:]
+	return ev, true
+}
+
+// query records that queryCursor is written right after a frame of the given height was flushed.
+func (r *mouseReader) query(height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queries = append(r.queries, height)
+}
+
+// line returns the line of the last frame displayed at the given row of the terminal. It is unknown until the
+// terminal replied to a query.
+func (r *mouseReader) line(row int) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.known {
+		return 0, false
+	}
+	return row - r.top, true
+}
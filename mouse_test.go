@@ -0,0 +1,101 @@
+package promptui
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestMouseReader(t *testing.T) {
+	tcs := []struct {
+		name    string
+		chunks  []string
+		queries []int
+		exp     string
+		events  []mouseEvent
+	}{
+		{
+			name:   "keys",
+			chunks: []string{"a\x1b[Ab"},
+			exp:    "a\x1b[Ab",
+		},
+		{
+			name:   "click",
+			chunks: []string{"\x1b[<0;5;12M\x1b[<0;5;12m"},
+			exp:    string(keyMouse),
+			events: []mouseEvent{{action: mouseClick, row: 12}},
+		},
+		{
+			name:   "wheel",
+			chunks: []string{"\x1b[<64;1;3M\x1b[<65;1;4Mx"},
+			exp:    string(keyMouse) + string(keyMouse) + "x",
+			events: []mouseEvent{{action: mouseWheelUp, row: 3}, {action: mouseWheelDown, row: 4}},
+		},
+		{
+			name:   "split event",
+			chunks: []string{"a\x1b[<0;", "5;7M"},
+			exp:    "a" + string(keyMouse),
+			events: []mouseEvent{{action: mouseClick, row: 7}},
+		},
+		{
+			name:   "ignored buttons",
+			chunks: []string{"\x1b[<2;1;1M\x1b[<32;1;1M"},
+			exp:    "",
+		},
+		{
+			name:    "reply",
+			chunks:  []string{"\x1b[10;1Rb"},
+			queries: []int{4},
+			exp:     "b",
+		},
+		{
+			name:   "reply without query",
+			chunks: []string{"\x1b[1;5R"},
+			exp:    "\x1b[1;5R",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newMouseReader(ioutil.NopCloser(&chunkReader{chunks: tc.chunks}))
+			for _, h := range tc.queries {
+				r.query(h)
+			}
+
+			out, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(out) != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, out)
+			}
+
+			for _, exp := range tc.events {
+				ev, ok := r.next()
+				if !ok || ev != exp {
+					t.Errorf("Expected event %v, got %v", exp, ev)
+				}
+			}
+			if ev, ok := r.next(); ok {
+				t.Errorf("Expected no more events, got %v", ev)
+			}
+		})
+	}
+}
+
+func TestMouseReaderLine(t *testing.T) {
+	r := newMouseReader(ioutil.NopCloser(&chunkReader{chunks: []string{"\x1b[10;1R"}}))
+
+	if _, ok := r.line(8); ok {
+		t.Errorf("Expected the line to be unknown before a reply")
+	}
+
+	r.query(4)
+	if _, err := ioutil.ReadAll(r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	line, ok := r.line(8)
+	if !ok || line != 2 {
+		t.Errorf("Expected line 2, got %d", line)
+	}
+}
@@ -0,0 +1,118 @@
+package promptui
+
+import (
+	"context"
+	"fmt"
+)
+
+// MultiSelect represents a list of items from which several items can be selected. The active item is selected
+// or deselected with the Toggle key, space by default, and the selection is submitted with the enter key.
+//
+// Navigation, search and paging behave like in a Select. Selected items are marked with the Checked template and
+// the others with the Unchecked template of the SelectTemplates.
+type MultiSelect struct {
+	Select
+
+	// MinSelections is the minimum number of items that must be selected before the selection can be submitted.
+	// Defaults to 0.
+	MinSelections int
+
+	// MaxSelections is the maximum number of items that can be selected. Once reached, other items cannot be
+	// selected until an item is deselected. Defaults to 0 for no maximum.
+	MaxSelections int
+}
+
+// Run executes the multi select list. It displays the label and the list of items, letting the user select any
+// number of items within the list. Run will keep the prompt alive until it has been canceled from the command
+// prompt or the selection has been submitted. It returns the indexes and the values of the selected items, in the
+// order of the list, and an error if any occurred during the select's execution.
+func (m *MultiSelect) Run() ([]int, []interface{}, error) {
+	s := &m.Select
+	if s.empty() {
+		return nil, nil, ErrEmptyList
+	}
+
+	if s.Size == 0 {
+		s.Size = 5
+	}
+
+	if m.MaxSelections > 0 && m.MinSelections > m.MaxSelections {
+		return nil, nil, fmt.Errorf("min selections %d is greater than max selections %d", m.MinSelections,
+			m.MaxSelections)
+	}
+
+	l, err := s.newList()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.list = l
+
+	s.setKeys()
+	if s.Keys.Toggle.Code == 0 {
+		s.Keys.Toggle = Key{Code: ' ', Display: "space"}
+	}
+
+	err = s.prepareTemplates()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.checked = map[int]bool{}
+	s.minChecked = m.MinSelections
+	s.maxChecked = m.MaxSelections
+
+	_, _, err = s.innerRun(context.Background(), 0, 0, ' ')
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexes := s.checkedIndexes()
+	if s.OnSelect != nil {
+		for _, i := range indexes {
+			s.OnSelect(i)
+		}
+	}
+	return indexes, s.checkedItems(), nil
+}
+
+// toggle selects or deselects the item at the given index inside a MultiSelect, unless the maximum number of
+// selected items is reached.
+func (s *Select) toggle(idx int) {
+	switch {
+	case s.checked[idx]:
+		delete(s.checked, idx)
+	case s.maxChecked > 0 && len(s.checked) >= s.maxChecked:
+	default:
+		s.checked[idx] = true
+	}
+}
+
+// checkedIndexes returns the indexes of the items selected inside a MultiSelect, in the order of the items.
+func (s *Select) checkedIndexes() []int {
+	indexes := []int{}
+	for i := 0; i < s.itemCount(); i++ {
+		if s.checked[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// checkedItems returns the items selected inside a MultiSelect, in the order of the items.
+func (s *Select) checkedItems() []interface{} {
+	values := []interface{}{}
+	for _, i := range s.checkedIndexes() {
+		values = append(values, s.item(i))
+	}
+	return values
+}
+
+// checkedData returns the data given to the Selected template for the items selected inside a MultiSelect.
+func (s *Select) checkedData() []interface{} {
+	values := []interface{}{}
+	for _, i := range s.checkedIndexes() {
+		values = append(values, s.itemData(i, s.item(i)))
+	}
+	return values
+}
@@ -0,0 +1,85 @@
+package promptui
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMultiSelectToggle(t *testing.T) {
+	t.Run("without a maximum", func(t *testing.T) {
+		s := Select{Items: []string{"a", "b", "c"}, checked: map[int]bool{}}
+
+		s.toggle(2)
+		s.toggle(0)
+		s.toggle(1)
+		s.toggle(1)
+
+		idx := s.checkedIndexes()
+		if !reflect.DeepEqual(idx, []int{0, 2}) {
+			t.Errorf("Expected indexes [0 2], got %v", idx)
+		}
+
+		items := s.checkedItems()
+		if !reflect.DeepEqual(items, []interface{}{"a", "c"}) {
+			t.Errorf("Expected items [a c], got %v", items)
+		}
+	})
+
+	t.Run("with a maximum", func(t *testing.T) {
+		s := Select{Items: []string{"a", "b", "c"}, checked: map[int]bool{}, maxChecked: 2}
+
+		s.toggle(0)
+		s.toggle(1)
+		s.toggle(2)
+		if idx := s.checkedIndexes(); !reflect.DeepEqual(idx, []int{0, 1}) {
+			t.Errorf("Expected indexes [0 1], got %v", idx)
+		}
+
+		s.toggle(0)
+		s.toggle(2)
+		if idx := s.checkedIndexes(); !reflect.DeepEqual(idx, []int{1, 2}) {
+			t.Errorf("Expected indexes [1 2], got %v", idx)
+		}
+	})
+}
+
+func TestMultiSelectEmptyList(t *testing.T) {
+	tcs := map[string]interface{}{
+		"empty slice": []string{},
+		"nil":         nil,
+	}
+
+	for name, items := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var out bufferCloser
+			m := MultiSelect{Select: Select{Items: items, Stdin: ioutil.NopCloser(strings.NewReader("")), Stdout: &out}}
+
+			if _, _, err := m.Run(); err != ErrEmptyList {
+				t.Errorf("Expected %v, got %v", ErrEmptyList, err)
+			}
+			if out.Len() != 0 {
+				t.Errorf("Expected no output, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestMultiSelectTemplateRender(t *testing.T) {
+	s := Select{Items: []string{"Zero"}}
+	err := s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	result := string(render(s.Templates.checked, "Zero"))
+	if result != IconGood {
+		t.Errorf("Expected checked mark to eq %q, got %q", IconGood, result)
+	}
+
+	result = string(render(s.Templates.unchecked, "Zero"))
+	if result != " " {
+		t.Errorf("Expected unchecked mark to eq %q, got %q", " ", result)
+	}
+}
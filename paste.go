@@ -0,0 +1,129 @@
+package promptui
+
+import (
+	"bytes"
+	"io"
+)
+
+// The sequences enabling and disabling the bracketed paste mode of the terminal, in which pasted text is sent
+// between the pasteStart and pasteEnd markers.
+const (
+	enableBracketedPaste  = esc + "?2004h"
+	disableBracketedPaste = esc + "?2004l"
+)
+
+var (
+	pasteStart = []byte(esc + "200~")
+	pasteEnd   = []byte(esc + "201~")
+)
+
+// pasteReader reads the input of a prompt, removing the bracketed paste markers and the control characters of
+// the pasted text so it is inserted literally, without a pasted line break submitting the prompt. In multi-line
+// input, the pasted line breaks are kept as enter keys, which insert line breaks.
+//
+// Input read outside of the markers, including all the input of terminals without bracketed paste, is kept as is.
+type pasteReader struct {
+	r         io.Reader
+	multiline bool
+
+	// pasting is true between the markers.
+	pasting bool
+
+	// cr is true after a pasted \r, so the \n of a \r\n line break is dropped.
+	cr bool
+
+	// pending holds the start of a marker split between two reads. out holds the filtered input not read yet.
+	pending []byte
+	out     []byte
+	buf     []byte
+}
+
+func newPasteReader(r io.Reader, multiline bool) *pasteReader {
+	return &pasteReader{r: r, multiline: multiline}
+}
+
+func (p *pasteReader) Read(b []byte) (int, error) {
+	for len(p.out) == 0 {
+		if len(p.buf) < len(b) {
+			p.buf = make([]byte, len(b))
+		}
+
+		n, err := p.r.Read(p.buf[:len(b)])
+		p.filter(p.buf[:n], err != nil)
+		zeroBytes(p.buf[:n])
+		if err != nil {
+			n := copy(b, p.out)
+			zeroBytes(p.out[:n])
+			p.out = p.out[n:]
+			return n, err
+		}
+	}
+
+	n := copy(b, p.out)
+	zeroBytes(p.out[:n])
+	p.out = p.out[n:]
+	return n, nil
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (p *pasteReader) Close() error {
+	if c, ok := p.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// clear zeroes and drops the input not read yet. The input already read is zeroed as it is read.
+func (p *pasteReader) clear() {
+	zeroBytes(p.pending)
+	zeroBytes(p.out)
+	p.pending, p.out = nil, nil
+}
+
+// filter appends the input to out without the markers and the pasted control characters. Unless final is set,
+// the start of a marker at the end of the input is kept pending until the next read. A lone escape is only kept
+// pending while pasting, since it is otherwise the escape key, expected without delay in vim mode.
+func (p *pasteReader) filter(data []byte, final bool) {
+	data = append(p.pending, data...)
+	p.pending = nil
+	defer zeroBytes(data)
+
+	for i := 0; i < len(data); {
+		rest := data[i:]
+		switch {
+		case bytes.HasPrefix(rest, pasteStart):
+			p.pasting = true
+			i += len(pasteStart)
+			continue
+		case bytes.HasPrefix(rest, pasteEnd):
+			p.pasting = false
+			p.cr = false
+			i += len(pasteEnd)
+			continue
+		case !final && (len(rest) > 1 || p.pasting) && len(rest) < len(pasteStart) &&
+			(bytes.HasPrefix(pasteStart, rest) || bytes.HasPrefix(pasteEnd, rest)):
+			p.pending = append([]byte(nil), rest...)
+			return
+		}
+
+		c := data[i]
+		i++
+
+		if !p.pasting {
+			p.out = append(p.out, c)
+			continue
+		}
+
+		cr := p.cr
+		p.cr = c == '\r'
+		switch {
+		case c == '\r' || c == '\n':
+			if p.multiline && !(c == '\n' && cr) {
+				p.out = append(p.out, byte(KeyEnter))
+			}
+		case c < ' ' || c == 0x7f:
+		default:
+			p.out = append(p.out, c)
+		}
+	}
+}
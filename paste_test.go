@@ -0,0 +1,62 @@
+package promptui
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestPasteReader(t *testing.T) {
+	tcs := []struct {
+		name      string
+		input     string
+		multiline bool
+		expected  string
+	}{
+		{"keeps typed input", "ab\r\x1b[A\x03", false, "ab\r\x1b[A\x03"},
+		{"removes the markers", "a\x1b[200~bc\x1b[201~d", false, "abcd"},
+		{"drops pasted control characters", "\x1b[200~a\r\nb\tc\x1b\x7f\x1b[201~\r", false, "abc\r"},
+		{"keeps pasted line breaks in multi-line input", "\x1b[200~a\r\nb\nc\rd\x1b[201~", true, "a\rb\rc\rd"},
+		{"keeps unicode", "\x1b[200~héllo 日本\x1b[201~", false, "héllo 日本"},
+		{"keeps a lone escape", "\x1b", false, "\x1b"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			readers := map[string]io.Reader{
+				"single read": strings.NewReader(tc.input),
+				"split reads": &splitReader{input: tc.input, size: 3},
+			}
+			for name, input := range readers {
+				out, err := ioutil.ReadAll(newPasteReader(input, tc.multiline))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if string(out) != tc.expected {
+					t.Errorf("Expected %q, got %q with %s", tc.expected, out, name)
+				}
+			}
+		})
+	}
+}
+
+// splitReader reads its input by chunks of the given size.
+type splitReader struct {
+	input string
+	size  int
+}
+
+func (r *splitReader) Read(b []byte) (int, error) {
+	if r.input == "" {
+		return 0, io.EOF
+	}
+
+	n := r.size
+	if n > len(r.input) {
+		n = len(r.input)
+	}
+	n = copy(b, r.input[:n])
+	r.input = r.input[n:]
+	return n, nil
+}
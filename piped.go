@@ -0,0 +1,203 @@
+package promptui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+	"github.com/logrhythm/promptui/list"
+	"github.com/logrhythm/promptui/screenbuf"
+)
+
+// readLine reads a line of r up to a \n, which is not included in the line like a trailing \r. The input is read
+// one byte at a time so the following lines are left in r for the next prompts. ErrEOF is returned if r is at
+// its end.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+
+		if err == io.EOF && len(line) > 0 {
+			break
+		}
+		if err == io.EOF {
+			return "", ErrEOF
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
+
+// pipedOutput returns a screen buffer writing to w, in Plain mode unless w is a terminal or the colors are forced.
+func pipedOutput(w io.Writer, forceColors bool) *screenbuf.ScreenBuf {
+	if w == nil {
+		w = readline.Stdout
+	}
+	sb := screenbuf.New(w, false)
+	sb.Plain = !forceColors && !isTerminal(w)
+	return sb
+}
+
+// runPiped reads the value of the prompt from a line of stdin when it is not a terminal, as if the line was typed
+// and submitted. An empty line submits the InitialValue or the Default.
+func (p *Prompt) runPiped(stdin io.Reader) (PromptResult, error) {
+	line, err := readLine(stdin)
+	if err == ErrEOF && p.EOFReturnsDefault {
+		return p.submitDefault(0)
+	}
+	if err != nil {
+		return PromptResult{}, err
+	}
+
+	value := line
+	if value == "" && !p.IsConfirm {
+		value = p.InitialValue
+		if value == "" {
+			value = p.Default
+		}
+	}
+
+	return p.submit(value)
+}
+
+// submitDefault submits the Default once the input ended with the given key, for EOFReturnsDefault.
+func (p *Prompt) submitDefault(key rune) (PromptResult, error) {
+	res, err := p.submit(p.Default)
+	res.Key = key
+	return res, err
+}
+
+// submit validates a value that was not typed in the prompt, and renders it with the success template like an
+// entered value. The error of Validate is returned since the value cannot be entered again.
+func (p *Prompt) submit(value string) (PromptResult, error) {
+	var err error
+
+	if err := p.validate(value, utf8.RuneCountInString(value)); err != nil {
+		return PromptResult{Value: value, Key: KeyEnter}, err
+	}
+
+	value = p.trim(value)
+	echo := p.answer(value)
+	answered := true
+
+	prompt := render(p.tpls.success, p.Label)
+	if p.IsConfirm {
+		yes, ok := confirmAnswer(value, p.Default)
+		if !ok {
+			prompt = render(p.tpls.invalid, p.Label)
+		}
+		if !yes {
+			err = ErrAbort
+		}
+		answered = ok
+	}
+	prompt = append(prompt, []byte(echo)...)
+	if p.PlainSuccess {
+		prompt = screenbuf.StripANSI(prompt)
+	}
+
+	sb := pipedOutput(p.Stdout, p.ForceColors)
+	sb.WriteLines(prompt)
+	sb.FlushFinal()
+	if answered {
+		transcribe(p.TranscriptWriter, p.Label, echo)
+	}
+
+	return PromptResult{Value: value, Key: KeyEnter}, err
+}
+
+// runPiped selects the item matching a line of stdin when it is not a terminal. The line is either the label of
+// an item, as formatted with %v, or its index. An empty line selects the item at the given cursor position. For a
+// MultiSelect, the line holds the labels or indexes of all the selected items, separated by commas. ErrNoMatch is
+// returned if an item cannot be found or is disabled.
+func (s *Select) runPiped(stdin io.Reader, cursorPos int) (int, interface{}, error) {
+	s.method = Unattended
+
+	line, err := readLine(stdin)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sb := pipedOutput(s.Stdout, s.ForceColors)
+
+	if s.checked != nil {
+		for _, field := range strings.Split(line, ",") {
+			if strings.TrimSpace(field) == "" {
+				continue
+			}
+
+			idx, ok := s.matchLine(field)
+			if !ok {
+				return 0, nil, ErrNoMatch
+			}
+			if s.maxChecked > 0 && !s.checked[idx] && len(s.checked) >= s.maxChecked {
+				return 0, nil, fmt.Errorf("more than %d items selected", s.maxChecked)
+			}
+			s.checked[idx] = true
+		}
+
+		if !s.canSubmit() {
+			return 0, nil, fmt.Errorf("less than %d items selected", s.minChecked)
+		}
+
+		s.renderSelected(sb, s.checkedData())
+		return 0, nil, nil
+	}
+
+	var idx int
+	if strings.TrimSpace(line) == "" {
+		s.list.SetCursor(cursorPos)
+		if !s.canSubmit() {
+			return 0, nil, ErrNoMatch
+		}
+		idx = s.itemIndex(s.list.Index())
+	} else {
+		var ok bool
+		if idx, ok = s.matchLine(line); !ok {
+			return 0, nil, ErrNoMatch
+		}
+	}
+
+	item := s.item(idx)
+	s.renderSelected(sb, []interface{}{s.itemData(idx, item)})
+	return idx, item, nil
+}
+
+// matchLine returns the index of the item whose label is the given line, or else whose index is the line. Disabled
+// items never match.
+func (s *Select) matchLine(line string) (int, bool) {
+	line = strings.TrimSpace(line)
+
+	idx := list.NotFound
+	for i := 0; i < s.itemCount(); i++ {
+		if strings.TrimSpace(fmt.Sprintf("%v", itemLabel(s.itemData(i, s.item(i))))) == line {
+			idx = i
+			break
+		}
+	}
+
+	if idx == list.NotFound {
+		n, err := strconv.Atoi(line)
+		if err != nil || n < 0 || n >= s.itemCount() {
+			return 0, false
+		}
+		idx = n
+	}
+
+	if s.isDisabled(idx) {
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,155 @@
+package promptui
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// bufferCloser is an output buffer usable as the Stdout of prompts and selects.
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error {
+	return nil
+}
+
+func TestReadLine(t *testing.T) {
+	r := strings.NewReader("first\r\nsecond\n\nlast")
+
+	for _, expected := range []string{"first", "second", "", "last"} {
+		line, err := readLine(r)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if line != expected {
+			t.Errorf("Expected %q, got %q", expected, line)
+		}
+	}
+
+	if _, err := readLine(r); err != ErrEOF {
+		t.Errorf("Expected ErrEOF, got %v", err)
+	}
+}
+
+func TestPromptPiped(t *testing.T) {
+	stdin := ioutil.NopCloser(strings.NewReader("value\n\nbad\n"))
+	var out bufferCloser
+
+	p := Prompt{
+		Label:   "Name",
+		Default: "default",
+		Stdin:   stdin,
+		Stdout:  &out,
+		Validate: func(v string) error {
+			if v == "bad" {
+				return errors.New("invalid")
+			}
+			return nil
+		},
+	}
+
+	for _, expected := range []string{"value", "default"} {
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if value != expected {
+			t.Errorf("Expected %q, got %q", expected, value)
+		}
+	}
+
+	if _, err := p.Run(); err == nil || err.Error() != "invalid" {
+		t.Errorf("Expected the validation error, got %v", err)
+	}
+
+	if _, err := p.Run(); err != ErrEOF {
+		t.Errorf("Expected ErrEOF, got %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Name: value\n") {
+		t.Errorf("Expected the submitted value in %q", out.String())
+	}
+}
+
+func TestPromptPipedConfirm(t *testing.T) {
+	p := Prompt{
+		Label:   "Continue",
+		Default: "y",
+		Stdin:   ioutil.NopCloser(strings.NewReader("maybe\n\nno\n")),
+		Stdout:  &bufferCloser{},
+	}
+
+	for _, expected := range []bool{true, false} {
+		yes, err := p.RunConfirm()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if yes != expected {
+			t.Errorf("Expected %v, got %v", expected, yes)
+		}
+	}
+}
+
+func TestSelectPiped(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		index    int
+		expected string
+		err      error
+	}{
+		{"label", "banana\n", 1, "banana", nil},
+		{"index", "2\n", 2, "cherry", nil},
+		{"empty line", "\n", 0, "apple", nil},
+		{"disabled", "date\n", 0, "", ErrNoMatch},
+		{"unknown", "fig\n", 0, "", ErrNoMatch},
+		{"out of range", "9\n", 0, "", ErrNoMatch},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Select{
+				Label:    "Fruit",
+				Items:    []string{"apple", "banana", "cherry", "date"},
+				Disabled: func(i int) bool { return i == 3 },
+				Stdin:    ioutil.NopCloser(strings.NewReader(tc.input)),
+				Stdout:   &bufferCloser{},
+			}
+
+			idx, value, err := s.Run()
+			if err != tc.err {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if idx != tc.index || value != tc.expected {
+				t.Errorf("Expected %d %q, got %d %q", tc.index, tc.expected, idx, value)
+			}
+		})
+	}
+}
+
+func TestMultiSelectPiped(t *testing.T) {
+	m := MultiSelect{
+		Select: Select{
+			Label:  "Fruits",
+			Items:  []string{"apple", "banana", "cherry"},
+			Stdin:  ioutil.NopCloser(strings.NewReader("cherry, 0\n")),
+			Stdout: &bufferCloser{},
+		},
+		MinSelections: 1,
+	}
+
+	indexes, items, err := m.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(indexes) != 2 || indexes[0] != 0 || indexes[1] != 2 {
+		t.Errorf("Expected indexes [0 2], got %v", indexes)
+	}
+	if len(items) != 2 || items[0] != "apple" || items[1] != "cherry" {
+		t.Errorf("Expected items [apple cherry], got %v", items)
+	}
+}
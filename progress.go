@@ -0,0 +1,144 @@
+package promptui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logrhythm/promptui/screenbuf"
+)
+
+// DefaultProgressInterval is the minimum time between two redraws of a progress bar when its Interval is not set.
+const DefaultProgressInterval = 100 * time.Millisecond
+
+// plainProgressInterval is the minimum time between two lines written by a progress bar whose output is not a
+// terminal.
+const plainProgressInterval = time.Second
+
+// ProgressBar displays the progress of some work on a single line, like "[#####     ] 50% (5/10)". The bar fills
+// the width of the terminal, which is looked up again on each redraw.
+//
+// When the output is not a terminal, the progress is written on a new line at most once per second.
+type ProgressBar struct {
+	// Interval is the minimum time between two redraws, so frequent updates do not flicker. Defaults to
+	// DefaultProgressInterval.
+	Interval time.Duration
+
+	// ForceColors keeps the redraw in place when the output is not a terminal.
+	ForceColors bool
+
+	w     io.Writer
+	total int
+
+	mu       sync.Mutex
+	sb       *screenbuf.ScreenBuf
+	plain    bool
+	current  int
+	drawn    time.Time
+	finished bool
+}
+
+// NewProgressBar creates a progress bar writing to w the progress of total units of work.
+func NewProgressBar(w io.Writer, total int) *ProgressBar {
+	return &ProgressBar{w: w, total: total}
+}
+
+// Set updates the number of units of work done and redraws the bar, unless it was redrawn less than Interval ago.
+// The value is kept between 0 and the total. Calling Set after Finish does nothing.
+func (p *ProgressBar) Set(current int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+
+	if current < 0 {
+		current = 0
+	}
+	if current > p.total {
+		current = p.total
+	}
+	p.current = current
+
+	p.init()
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	if p.plain {
+		interval = plainProgressInterval
+	}
+
+	now := time.Now()
+	if !p.drawn.IsZero() && now.Sub(p.drawn) < interval {
+		return
+	}
+	p.drawn = now
+
+	p.sb.WriteString(p.line(p.width()))
+	p.sb.Flush()
+}
+
+// Finish redraws the bar a last time with the last value set and leaves the cursor on the next line, so any
+// following output appears below the bar.
+func (p *ProgressBar) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+	p.finished = true
+
+	p.init()
+	p.sb.WriteString(p.line(p.width()))
+	p.sb.FlushFinal()
+}
+
+// init creates the screen buffer on the first update. It must be called with the lock held.
+func (p *ProgressBar) init() {
+	if p.sb != nil {
+		return
+	}
+
+	p.plain = !p.ForceColors && !isTerminal(p.w)
+	p.sb = screenbuf.New(p.w, true)
+	p.sb.Plain = p.plain
+	p.sb.ReflowOnResize = true
+}
+
+// width returns the number of columns the line of the bar can use. The last column of the terminal is left empty
+// so the line never wraps.
+func (p *ProgressBar) width() int {
+	if p.plain {
+		return screenbuf.DefaultWidth - 1
+	}
+	return p.sb.Width() - 1
+}
+
+// line returns the line of the bar filling the given number of columns.
+func (p *ProgressBar) line(width int) string {
+	percent := 100
+	if p.total > 0 {
+		percent = p.current * 100 / p.total
+	}
+	status := fmt.Sprintf(" %d%% (%d/%d)", percent, p.current, p.total)
+
+	// the size of the bar leaves room for the longest status, so it does not change while the work progresses.
+	longest := fmt.Sprintf(" 100%% (%d/%d)", p.total, p.total)
+	size := width - len(longest) - 2
+	if size < 1 {
+		size = 1
+	}
+
+	filled := size
+	if p.total > 0 {
+		filled = size * p.current / p.total
+	}
+
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(" ", size-filled) + "]" + status
+}
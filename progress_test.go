@@ -0,0 +1,80 @@
+package promptui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgressBarLine(t *testing.T) {
+	tcs := []struct {
+		name     string
+		total    int
+		current  int
+		width    int
+		expected string
+	}{
+		{"half", 10, 5, 25, "[#####     ] 50% (5/10)"},
+		{"empty", 10, 0, 25, "[          ] 0% (0/10)"},
+		{"complete", 4, 4, 20, "[#######] 100% (4/4)"},
+		{"narrow", 10, 5, 5, "[ ] 50% (5/10)"},
+		{"no total", 0, 0, 16, "[###] 100% (0/0)"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProgressBar(&bytes.Buffer{}, tc.total)
+			p.current = tc.current
+			got := p.line(tc.width)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	t.Run("redraws in place", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := NewProgressBar(&buf, 10)
+		p.ForceColors = true
+		p.init()
+		p.sb.SetWidth(26)
+
+		p.Set(5)
+		p.Set(20)
+		p.Finish()
+
+		out := buf.String()
+		for _, line := range []string{"[#####     ] 50% (5/10)", "[##########] 100% (10/10)"} {
+			if !strings.Contains(out, line) {
+				t.Errorf("Expected line %q in %q", line, out)
+			}
+		}
+		if strings.Count(out, "\n") != 1 {
+			t.Errorf("Expected a single line in %q", out)
+		}
+	})
+
+	t.Run("throttles the redraws", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := NewProgressBar(&buf, 10)
+
+		for i := 1; i <= 10; i++ {
+			p.Set(i)
+		}
+		p.Finish()
+		p.Set(3)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 lines, got %q", lines)
+		}
+		if !strings.HasSuffix(lines[0], "] 10% (1/10)") || !strings.HasSuffix(lines[1], "] 100% (10/10)") {
+			t.Errorf("Unexpected lines %q", lines)
+		}
+		if len(lines[1]) != 79 {
+			t.Errorf("Expected lines of 79 columns, got %d", len(lines[1]))
+		}
+	})
+}
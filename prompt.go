@@ -1,15 +1,20 @@
 package promptui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"regexp"
+	"reflect"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/chzyer/readline"
-	"github.com/manifoldco/promptui/screenbuf"
+	"github.com/logrhythm/promptui/screenbuf"
 )
 
 // Prompt represents a single line text field input with options for validation and input masks.
@@ -24,6 +29,11 @@ type Prompt struct {
 	// and the user will be able to view or change it depending on the options.
 	Default string
 
+	// InitialValue is an optional value the input starts with, with the cursor placed at its end. Unlike
+	// Default, it is not a fallback: it is regular text the user can edit or clear, so clearing it and
+	// submitting returns an empty value. It takes precedence over Default for the initial input.
+	InitialValue string
+
 	// AllowEdit lets the user edit the default value. If false, any key press
 	// other than <Enter> automatically clears the default value.
 	AllowEdit bool
@@ -31,10 +41,39 @@ type Prompt struct {
 	// Validate is an optional function that fill be used against the entered value in the prompt to validate it.
 	Validate ValidateFunc
 
+	// ValidateContext is an optional function validating the entered value like Validate, but also given the
+	// state of the prompt, like whether the value is the unchanged Default. It takes precedence over Validate
+	// when both are set.
+	ValidateContext func(ValidationInput) error
+
+	// ValidateLive displays the error returned by Validate below the prompt after every key press instead of
+	// only after the value is submitted. Since Validate is then called on each key press, it should be cheap
+	// to execute.
+	ValidateLive bool
+
+	// MaxAttempts is the number of invalid values that can be submitted before Run returns the last one with
+	// ErrMaxAttempts, so scripted runs feeding invalid values cannot loop forever. The validation error is
+	// displayed after each of them. Defaults to 0 for no limit.
+	MaxAttempts int
+
+	// TrimSpace removes the leading and trailing white space of the value once it is validated, for values like
+	// names where stray spaces are typos. The Default is trimmed the same way when it is returned. By default, the
+	// value is returned as entered, spaces included, as needed for passwords.
+	TrimSpace bool
+
 	// Mask is an optional rune that sets which character to display instead of the entered characters. This
 	// allows hiding private information like passwords.
+	//
+	// The buffers a masked input is read and edited in are zeroed before the prompt returns. This is a best
+	// effort: Go strings are immutable and cannot be zeroed, so the returned value, like the values given to
+	// Validate and KeyHandler, stays in memory until it is garbage collected.
 	Mask rune
 
+	// MaskMode sets how the masked input is displayed, with the Mask once for each character by default. The
+	// other modes do not disclose the length of the input, for passwords typed in front of others or in screen
+	// recordings. The input can still be edited as usual, even when nothing is displayed.
+	MaskMode MaskMode
+
 	// Templates can be used to customize the prompt output. If nil is passed, the
 	// default templates are used. See the PromptTemplates docs for more info.
 	Templates *PromptTemplates
@@ -43,14 +82,122 @@ type Prompt struct {
 	// most properties related to input will be ignored.
 	IsConfirm bool
 
+	// SingleKey makes a confirm prompt submit its answer as soon as y or n is pressed, without waiting for
+	// <Enter>. <Enter> still submits the Default answer.
+	SingleKey bool
+
 	// IsVimMode enables vi-like movements (hjkl) and editing.
 	IsVimMode bool
 
 	// the Pointer defines how to render the cursor.
 	Pointer Pointer
 
-	stdin  io.ReadCloser
-	stdout io.WriteCloser
+	// Suggest is an optional function called with the current input when the tab key is pressed. If a single
+	// suggestion is returned, the input is completed with it. If several suggestions are returned, they are
+	// listed below the prompt and pressing tab again cycles through them. The list is cleared when any other
+	// key is pressed.
+	Suggest func(input string) []string
+
+	// Confirm asks for the value a second time when Mask is set, as usually done for new passwords, with the
+	// label rendered by the ConfirmLabel template. An ErrMismatch error is returned if both values are different.
+	Confirm bool
+
+	// ConfirmRetries is the number of times both values are asked again when they are different before
+	// returning ErrMismatch. Defaults to 0.
+	ConfirmRetries int
+
+	// InputFilter is an optional function called for each typed character. Characters for which it returns
+	// false are dropped before reaching the input, unlike Validate which only flags an invalid value. See
+	// FilterDigits and FilterFloat.
+	InputFilter InputFilter
+
+	// MaxLength is the maximum number of characters of the input. The characters typed or pasted beyond it are
+	// dropped, like those rejected by InputFilter. The length and maxLength template functions return the number
+	// of characters of the input and MaxLength, for a counter like `{{ . }} ({{ length }}/{{ maxLength }})` in
+	// the Prompt template. The values that are not typed, like the Default or a piped value, are not cut, so
+	// Validate should still check the length if it matters. Defaults to 0 for no maximum.
+	MaxLength int
+
+	// Bell rings the bell of the terminal when a typed character is dropped, by InputFilter or beyond MaxLength,
+	// which flashes the terminals with a visual bell. Nothing is rung when the output is not a terminal.
+	Bell bool
+
+	// KeyHandler is an optional function called for each key press before the default handling, with the
+	// current input. If it returns true, the default handling is skipped and the input is replaced by the
+	// returned runes.
+	//
+	// Printable characters are received as is. The arrow keys, enter, backspace and tab are received as the
+	// KeyPrev, KeyNext, KeyBackward, KeyForward, KeyEnter, KeyBackspace and KeyTab runes. Other control keys
+	// are received as their control code, for example 23 for <Ctrl+W> or 18 for <Ctrl+R>.
+	KeyHandler func(key rune, buf []rune) (handled bool, newBuf []rune)
+
+	// ExitKeys are optional keys ending the prompt without submitting the value, in which case ErrAbort is
+	// returned. RunResult reports which key was pressed.
+	ExitKeys []rune
+
+	// Multiline lets the user enter several lines. Pressing <Enter> inserts a line break, the arrow keys move
+	// between lines and the input is submitted by pressing <Ctrl+D> on an empty line or the SubmitKey. The
+	// returned value contains the line breaks.
+	Multiline bool
+
+	// SubmitKey is an optional key submitting the input in Multiline mode in addition to <Ctrl+D> pressed on
+	// an empty line.
+	SubmitKey rune
+
+	// History is an optional list of previous entries, from the oldest to the most recent. The up and down
+	// arrow keys (k and j in vim normal mode) recall them into the input, where they can be edited before
+	// submission. The slice is never modified by the prompt.
+	History []string
+
+	// EOFReturnsDefault submits the Default when the input ends, like a pipe with no line left or <Ctrl+D>
+	// pressed on an empty input, instead of returning ErrEOF. The Default is checked by Validate like an entered
+	// value, so non-interactive pipelines can fall back to it.
+	EOFReturnsDefault bool
+
+	// ForceColors keeps the styles and the cursor movements when the output is not a terminal. By default, the
+	// prompt is written as plain text appended line by line to pipes and files.
+	ForceColors bool
+
+	// PlainSuccess removes the styles from the line left once the prompt is submitted, keeping them while the
+	// value is typed, for outputs that are both displayed and logged.
+	PlainSuccess bool
+
+	// TranscriptWriter is an optional writer to which a plain "Label: answer" line is appended once a value is
+	// submitted, for an audit log of what was asked and answered. A masked answer is written masked. Nothing is
+	// written when the prompt is aborted or its input ends.
+	TranscriptWriter io.Writer
+
+	// Icons is the icon set used by the default templates and the icon functions of the templates. Defaults to the
+	// package icons, such as IconInitial.
+	Icons *IconSet
+
+	// Timeout is an optional delay after which the prompt stops waiting for input, returning the Default value
+	// with ErrTimeout. Each key press restarts the delay. The timeLeft template function returns the number of
+	// seconds left, for example `{{ . }} ({{ timeLeft }}s)` in the Prompt template.
+	Timeout time.Duration
+
+	// deadline is the time at which the Timeout elapses, for the timeLeft template function.
+	deadline time.Time
+
+	// length is the number of characters of the input being drawn, for the length template function.
+	length int
+
+	// password is set by ReadPassword, leaving the bracketed paste mode disabled.
+	password bool
+
+	// tpls are the compiled Templates with their template functions reading the state of this prompt.
+	tpls promptTemplateSet
+
+	// Stdin is the input of the prompt. Defaults to os.Stdin. When it is not a terminal, like a pipe, the value
+	// is read from a single line of it instead, as if it was typed and submitted.
+	Stdin io.ReadCloser
+
+	// Stdout is the output of the prompt. Defaults to os.Stdout.
+	Stdout io.WriteCloser
+
+	// Terminal replaces the terminal of the process, using Stdin and Stdout as its input and output even if they
+	// are not terminals. Tests set it to a TestTerminal to type scripted keys into the prompt.
+	Terminal Terminal
 }
 
 // PromptTemplates allow a prompt to be customized following stdlib
@@ -94,112 +241,616 @@ type PromptTemplates struct {
 	// the prompt's validation function.
 	ValidationError string
 
+	// Suggestion is a text/template for each of the suggestions listed below the prompt when the Suggest
+	// function returns several values. It receives a PromptSuggestion.
+	Suggestion string
+
+	// ConfirmLabel is a text/template for the label displayed when a prompt with Confirm set asks for the value
+	// a second time. It receives the Label of the prompt and defaults to "Confirm".
+	ConfirmLabel string
+
 	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
 	// documentation.
 	//
 	// By default, FuncMap contains the color functions used to color the text in templates. If FuncMap
 	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
+	//
+	// The templates are compiled with the functions of FuncMap at the first run. The functions added to or
+	// replaced in the map afterwards are used once PrepareTemplates is called.
 	FuncMap template.FuncMap
 
-	prompt     *template.Template
-	valid      *template.Template
-	invalid    *template.Template
-	validation *template.Template
-	success    *template.Template
+	promptTemplateSet
+
+	// compiled identifies what the templates were compiled from, so they are only compiled again when it changes.
+	compiled promptTemplatesKey
+}
+
+// promptTemplateSet holds the compiled templates of a prompt.
+type promptTemplateSet struct {
+	prompt       *template.Template
+	valid        *template.Template
+	invalid      *template.Template
+	validation   *template.Template
+	success      *template.Template
+	suggestion   *template.Template
+	confirmLabel *template.Template
+}
+
+// bind returns a copy of the templates whose functions of the same names as funcs are replaced by them.
+func (t promptTemplateSet) bind(funcs template.FuncMap) promptTemplateSet {
+	return promptTemplateSet{
+		prompt:       bindTemplate(t.prompt, funcs),
+		valid:        bindTemplate(t.valid, funcs),
+		invalid:      bindTemplate(t.invalid, funcs),
+		validation:   bindTemplate(t.validation, funcs),
+		success:      bindTemplate(t.success, funcs),
+		suggestion:   bindTemplate(t.suggestion, funcs),
+		confirmLabel: bindTemplate(t.confirmLabel, funcs),
+	}
+}
+
+// promptTemplatesKey identifies the template strings, the FuncMap, the icons and the kind of prompt templates
+// were compiled from.
+type promptTemplatesKey struct {
+	isConfirm  bool
+	confirmYes bool
+	sources    [8]string
+	funcMap    uintptr
+	icons      IconSet
+}
+
+// PromptResult is the outcome of a prompt returned by RunResult.
+type PromptResult struct {
+	// Value is the value entered in the prompt.
+	Value string
+
+	// Key is the key that ended the prompt. It is KeyEnter when the value was submitted, or the key used to
+	// submit a multi-line value. Otherwise, it is one of the ExitKeys, 3 for <Ctrl+C>, 4 for <Ctrl+D> or 0
+	// when the input was closed.
+	Key rune
+}
+
+// MaskMode sets how a Prompt displays its input when Mask is set.
+type MaskMode int
+
+const (
+	// MaskPerChar displays the Mask once for each character of the input.
+	MaskPerChar MaskMode = iota
+
+	// MaskFixed displays the Mask MaskFixedLength times once something is typed, whatever the length of the
+	// input, with the cursor after them.
+	MaskFixed
+
+	// MaskHidden displays nothing but the cursor, which does not move, like the passwords asked by sudo.
+	MaskHidden
+)
+
+// MaskFixedLength is the number of masks displayed for a non-empty input with MaskFixed.
+const MaskFixedLength = 8
+
+// PromptSuggestion is the data given to the Suggestion template for each suggestion listed below a prompt.
+type PromptSuggestion struct {
+	// Value is the suggested input.
+	Value string
+
+	// Active is true when the suggestion is the one currently completing the input.
+	Active bool
 }
 
 // Run executes the prompt. Its displays the label and default value if any, asking the user to enter a value.
 // Run will keep the prompt alive until it has been canceled from the command prompt or it has received a valid
 // value. It will return the value and an error if any occurred during the prompt's execution.
 func (p *Prompt) Run() (string, error) {
+	return p.RunContext(context.Background())
+}
+
+// RunContext executes the prompt like Run, but also stops waiting for input when the context is done. In that
+// case, the returned error wraps the context error and matches ErrAbort when using errors.Is.
+func (p *Prompt) RunContext(ctx context.Context) (string, error) {
+	res, err := p.runResult(ctx)
+	return res.Value, err
+}
+
+// RunResult executes the prompt like Run, but returns a PromptResult also holding the key that ended the
+// prompt. This lets callers tell apart the different ways to leave a prompt, for example one of the ExitKeys
+// used to go back to a previous step from <Ctrl+C> used to quit.
+func (p *Prompt) RunResult() (PromptResult, error) {
+	return p.runResult(context.Background())
+}
+
+// RunWith submits the given value without waiting for input, like a value coming from a flag. The value is
+// checked by Validate and displayed with the Success template, so the output is the same as when it is typed.
+// The error of Validate is returned instead if the value is invalid. For a confirm prompt, ErrAbort is returned
+// unless the value is an answer yes.
+func (p *Prompt) RunWith(value string) (string, error) {
+	if err := p.prepareTemplates(); err != nil {
+		return "", err
+	}
+
+	res, err := p.submit(value)
+	return res.Value, err
+}
+
+// RunConfirm executes the prompt as a yes or no question like IsConfirm, and returns whether the answer is yes.
+// The answers y and yes are true, n and no are false, in any case, and pressing <Enter> alone answers the Default,
+// "y" or "n". The question is asked again until a valid answer is given. Unlike Run, ErrAbort is returned when
+// <Ctrl+C> is pressed.
+func (p *Prompt) RunConfirm() (bool, error) {
+	return p.runConfirm(context.Background())
+}
+
+// ReadPassword asks for a password on the terminal of the process, with the given label. The typed characters are
+// displayed as *, and the prompt has no default nor history. The bracketed paste mode is left disabled, so the
+// terminal does not echo the markers of a paste, and the buffers the input was read in are cleared before
+// returning. The returned string itself cannot be cleared.
+func ReadPassword(label string) (string, error) {
+	p := Prompt{Label: label, Mask: '*', password: true}
+	return p.Run()
+}
+
+func (p *Prompt) runConfirm(ctx context.Context) (bool, error) {
+	confirm := *p
+	confirm.IsConfirm = true
+
+	var inputErr error
+	for {
+		res, err := confirm.run(ctx, inputErr)
+		if err == ErrInterrupt {
+			return false, ErrAbort
+		}
+		if err != nil && (err != ErrAbort || p.isExitKey(res.Key)) {
+			return false, err
+		}
+
+		yes, ok := confirmAnswer(res.Value, p.Default)
+		if ok {
+			return yes, nil
+		}
+		inputErr = errConfirmAnswer
+	}
+}
+
+func (p *Prompt) runResult(ctx context.Context) (PromptResult, error) {
+	if p.Confirm && p.Mask != 0 {
+		return p.runConfirmed(ctx)
+	}
+	return p.run(ctx, nil)
+}
+
+// runConfirmed asks for the value twice, asking both values again up to ConfirmRetries times while they differ.
+// The value is written to the TranscriptWriter once confirmed.
+func (p *Prompt) runConfirmed(ctx context.Context) (PromptResult, error) {
+	if err := p.prepareTemplates(); err != nil {
+		return PromptResult{}, err
+	}
+
+	first := *p
+	first.TranscriptWriter = nil
+
+	// the value is typed again from scratch.
+	confirm := first
+	confirm.Label = string(render(p.tpls.confirmLabel, p.Label))
+	confirm.Default = ""
+	confirm.InitialValue = ""
+	confirm.History = nil
+	confirm.Suggest = nil
+
+	var inputErr error
+	for attempt := 0; ; attempt++ {
+		res, err := first.run(ctx, inputErr)
+		if err != nil {
+			return res, err
+		}
+
+		confirmed, err := confirm.run(ctx, nil)
+		if err != nil {
+			return confirmed, err
+		}
+
+		if res.Value == confirmed.Value {
+			transcribe(p.TranscriptWriter, p.Label, p.answer(confirmed.Value))
+			return confirmed, nil
+		}
+
+		if attempt >= p.ConfirmRetries {
+			return PromptResult{Key: confirmed.Key}, ErrMismatch
+		}
+		inputErr = ErrMismatch
+	}
+}
+
+// run executes a single prompt. If inputErr is not nil, it is displayed with the validation template until the
+// first key is pressed.
+func (p *Prompt) run(ctx context.Context, inputErr error) (PromptResult, error) {
 	var err error
 
 	err = p.prepareTemplates()
 	if err != nil {
-		return "", err
+		return PromptResult{}, err
+	}
+
+	var stdin io.ReadCloser = readline.Stdin
+	if p.Stdin != nil {
+		stdin = p.Stdin
+	}
+	if p.Terminal == nil && !isTerminal(stdin) {
+		return p.runPiped(stdin)
 	}
+	if p.Stdin == nil {
+		stdin = readline.NewCancelableStdin(stdin)
+	}
+
+	// the input is read through a pasteReader, so pasted text is inserted as is, then through a KeyReader, so
+	// the keys are the same on all terminals.
 
+	paste := newPasteReader(stdin, p.Multiline)
+	keys := NewKeyReader(paste)
+	if p.Mask != 0 {
+		defer func() {
+			keys.clear()
+			paste.clear()
+		}()
+	}
+
+	// readline waits for the pending read of its input when it is closed, so the keys are read through a
+	// CancelableStdin canceled first when the prompt stops waiting for them, on a timeout or a done context.
+	cancelable := readline.NewCancelableStdin(keys)
 	c := &readline.Config{
-		Stdin:          p.stdin,
-		Stdout:         p.stdout,
+		Stdin:          cancelable,
+		Stdout:         p.Stdout,
 		EnableMask:     p.Mask != 0,
 		MaskRune:       p.Mask,
 		HistoryLimit:   -1,
 		VimMode:        p.IsVimMode,
 		UniqueEditLine: true,
 	}
+	configure(c, p.Terminal)
 
 	err = c.Init()
 	if err != nil {
-		return "", err
+		return PromptResult{}, err
 	}
 
+	plain := !p.ForceColors && p.Terminal == nil && !isTerminal(c.Stdout)
+	out := c.Stdout
+	c.Stdout = bellFilter{out}
+
 	rl, err := readline.NewEx(c)
 	if err != nil {
-		return "", err
+		return PromptResult{}, err
 	}
-	// we're taking over the cursor,  so stop showing it.
-	rl.Write([]byte(hideCursor))
-	sb := screenbuf.New(rl, false)
 
-	validFn := func(x string) error {
-		return nil
+	bracketedPaste := !plain && !p.password
+	guard := &panicGuard{rl: rl}
+	if !plain {
+		// we're taking over the cursor,  so stop showing it.
+		rl.Write([]byte(hideCursor))
+		guard.reset = showCursor
 	}
-	if p.Validate != nil {
-		validFn = p.Validate
+	if bracketedPaste {
+		rl.Write([]byte(enableBracketedPaste))
+		guard.reset = disableBracketedPaste + guard.reset
 	}
+	defer guard.restore()
+	sb := newScreenBuf(rl, false, p.Terminal)
+	sb.Plain = plain
+
+	interrupt := closerFunc(func() error {
+		cancelable.Close()
+		return rl.Close()
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	closeOnDone(ctx, done, interrupt)
 
-	var inputErr error
 	input := p.Default
 	if p.IsConfirm {
 		input = ""
 	}
 	eraseDefault := input != "" && !p.AllowEdit
+	if p.InitialValue != "" && !p.IsConfirm {
+		input = p.InitialValue
+		eraseDefault = false
+	}
 	cur := NewCursor(input, p.Pointer, eraseDefault)
+	hist := newHistory(p.History)
 
-	listen := func(input []rune, pos int, key rune) ([]rune, int, bool) {
-		_, _, keepOn := cur.Listen(input, pos, key)
-		err := validFn(cur.Get())
+	// a masked input is zeroed once the prompt returns, and its copies are avoided where possible.
+	if p.Mask != 0 {
+		cur.scrub = true
+		defer cur.clear()
+	}
+
+	validFn := func() error {
+		return p.validate(cur.Get(), cur.Position)
+	}
+
+	var suggestions []string
+	suggestion := -1
+
+	complete := func() {
+		if len(suggestions) > 0 {
+			suggestion = (suggestion + 1) % len(suggestions)
+			cur.erase = false
+			cur.Replace(suggestions[suggestion])
+			return
+		}
+
+		found := p.Suggest(cur.Get())
+		switch len(found) {
+		case 0:
+		case 1:
+			cur.erase = false
+			cur.Replace(found[0])
+		default:
+			suggestions = found
+			suggestion = -1
+		}
+	}
+
+	draw := func() {
+		err := validFn()
 		var prompt []byte
+		p.length = len(cur.input)
 
 		if err != nil {
-			prompt = render(p.Templates.invalid, p.Label)
+			prompt = render(p.tpls.invalid, p.Label)
 		} else {
-			prompt = render(p.Templates.valid, p.Label)
+			prompt = render(p.tpls.valid, p.Label)
 			if p.IsConfirm {
-				prompt = render(p.Templates.prompt, p.Label)
+				prompt = render(p.tpls.prompt, p.Label)
 			}
 		}
 
-		echo := cur.Format()
-		if p.Mask != 0 {
-			echo = cur.FormatMask(p.Mask)
-		}
+		prompt = append(prompt, []byte(p.echo(&cur))...)
 
-		prompt = append(prompt, []byte(echo)...)
-		sb.Reset()
-		sb.Write(prompt)
+		// the frame is written over the previous one without a Reset, so its unchanged lines are skipped.
+		sb.WriteLines(prompt)
+		for i, value := range suggestions {
+			sb.Write(render(p.tpls.suggestion, PromptSuggestion{Value: value, Active: i == suggestion}))
+		}
 		if inputErr != nil {
-			validation := render(p.Templates.validation, inputErr)
+			validation := render(p.tpls.validation, inputErr)
 			sb.Write(validation)
 			inputErr = nil
+		} else if p.ValidateLive && err != nil {
+			sb.Write(render(p.tpls.validation, err))
 		}
 		sb.Flush()
+	}
+
+	// mu guards the input, the validation error and the output, shared by readline, the timeout goroutine and
+	// the loop reading the lines. Once finished is set, the callbacks stop touching them.
+	var mu sync.Mutex
+	timedOut := false
+	finished := false
+	resetTimeout := func() {
+		if p.Timeout > 0 {
+			p.deadline = time.Now().Add(p.Timeout)
+		}
+	}
+	resetTimeout()
+
+	if p.Timeout > 0 {
+		go func() {
+			defer guard.catch()
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+
+			mu.Lock()
+			left := p.timeLeft()
+			mu.Unlock()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+				}
+
+				// the lock is released before closing readline, which waits for the key filter, and when draw
+				// panics.
+				stop, expired := func() (bool, bool) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					if finished {
+						return true, false
+					}
+					if !time.Now().Before(p.deadline) {
+						timedOut = true
+						return true, true
+					}
+					if l := p.timeLeft(); l != left {
+						left = l
+						draw()
+					}
+					return false, false
+				}()
+
+				if expired {
+					interrupt.Close()
+				}
+				if stop {
+					return
+				}
+			}
+		}()
+	}
+
+	listen := func(input []rune, pos int, key rune) ([]rune, int, bool) {
+		defer guard.catch()
+		mu.Lock()
+		defer mu.Unlock()
+
+		// the key ending the line is passed on once the line is returned, and would draw over the validation
+		// error of the next attempt.
+		if finished || key == KeyEnter || key == readline.CharCtrlJ {
+			return nil, 0, true
+		}
+
+		keepOn := true
+		suggestions = nil
+		switch {
+		case key == KeyPrev && p.Multiline:
+			cur.LineUp()
+		case key == KeyNext && p.Multiline:
+			cur.LineDown()
+		case key == KeyPrev:
+			if entry, ok := hist.prev(cur.Get()); ok {
+				cur.erase = false
+				cur.Replace(entry)
+			}
+		case key == KeyNext:
+			if entry, ok := hist.next(); ok {
+				cur.erase = false
+				cur.Replace(entry)
+			}
+		default:
+			_, _, keepOn = cur.Listen(input, pos, key)
+		}
+
+		draw()
 		return nil, 0, keepOn
 	}
 
 	c.SetListener(listen)
 
-	for {
-		_, err = rl.Readline()
-		inputErr = validFn(cur.Get())
-		if inputErr == nil {
-			break
+	// endKey is the key that ended the line read by readline. Once it is set, the keys typed before the line is
+	// read are dropped, so they can neither replace it nor edit the submitted input.
+	var endKey rune
+	ended := false
+	end := func(r rune) {
+		endKey = r
+		ended = true
+	}
+
+	// Custom key handlers and some keys are handled before readline sees them. Readline rings the bell on tab
+	// when it has no completer of its own and returns the line as soon as enter is pressed, even in multi-line
+	// input.
+	exit := false
+
+	// keep keeps a key from readline. Readline stops reading the input after <Enter>, <Ctrl+J>, <Ctrl+C> and
+	// <Ctrl+D> until its line ends, so it is told to read on when the prompt keeps one of them.
+	keep := func(r rune) (rune, bool) {
+		if r == KeyEnter || r == readline.CharCtrlJ || r == readline.CharInterrupt || r == readline.CharDelete {
+			rl.Terminal.KickRead()
 		}
+		return r, false
+	}
 
-		if err != nil {
-			break
+	c.FuncFilterInputRune = func(r rune) (rune, bool) {
+		defer guard.catch()
+		mu.Lock()
+		defer mu.Unlock()
+
+		// readline reads 0 over and over once the input is closed, which is passed on so it ends the next line.
+		if ended || finished {
+			return r, r == 0
+		}
+
+		resetTimeout()
+		if p.isExitKey(r) {
+			exit = true
+			end(r)
+			return readline.CharInterrupt, true
+		}
+
+		if p.KeyHandler != nil && r != 0 {
+			if handled, buf := p.KeyHandler(r, []rune(cur.Get())); handled {
+				suggestions = nil
+				cur.erase = false
+				cur.Replace(string(buf))
+				draw()
+				return keep(r)
+			}
 		}
+
+		// a default about to be erased does not count toward MaxLength, since the typed character replaces it.
+		filtered := p.InputFilter != nil && unicode.IsPrint(r) && !p.InputFilter(r, cur.Position)
+		if filtered || p.MaxLength > 0 && unicode.IsPrint(r) && !cur.erase && len(cur.input) >= p.MaxLength {
+			if p.Bell && !plain {
+				out.Write([]byte(bell))
+			}
+			return r, false
+		}
+
+		switch {
+		case p.IsConfirm && p.SingleKey && strings.ContainsRune("yYnN", r):
+			suggestions = nil
+			cur.erase = false
+			cur.Replace(string(r))
+			draw()
+			end(KeyEnter)
+			return KeyEnter, true
+		case r == KeyDelete, r == KeyDeleteWord, r == KeyKillToEnd, r == KeyKillToStart, r == KeyLineStart,
+			r == KeyLineEnd:
+			// readline rings the bell instead of passing these keys on in vim normal mode, and would insert KeyDelete
+			suggestions = nil
+			cur.Listen(nil, 0, r)
+			draw()
+			return r, false
+		case r == KeyRedraw:
+			// readline clears the screen itself, but the lines of the prompt are not drawn again.
+			sb.ClearScreen()
+			draw()
+			return r, false
+		case r == KeyTab && p.Suggest != nil:
+			complete()
+			draw()
+			return r, false
+		case r == KeyPageUp, r == KeyPageDown:
+			// a prompt has no pages, and readline would insert these runes.
+			return r, false
+		case !p.Multiline:
+		case r == p.SubmitKey && p.SubmitKey != 0,
+			r == readline.CharDelete && cur.lineStart(cur.Position) == cur.lineEnd(cur.Position):
+			end(r)
+			return KeyEnter, true
+		case r == readline.CharDelete:
+			return keep(r)
+		case r == KeyEnter:
+			suggestions = nil
+			cur.erase = false
+			cur.Update("\n")
+			draw()
+			return keep(r)
+		}
+
+		// the line of readline is always empty, so <Ctrl+D> ends it like <Enter> and <Ctrl+C>.
+		if r == KeyEnter || r == readline.CharCtrlJ || r == readline.CharInterrupt || r == readline.CharDelete {
+			end(r)
+		}
+		return r, true
+	}
+
+	for attempts := 1; !finished; attempts++ {
+		_, err = rl.Readline()
+
+		mu.Lock()
+		inputErr = validFn()
+		switch {
+		case inputErr == nil, err != nil:
+			finished = true
+		case p.MaxAttempts > 0 && attempts >= p.MaxAttempts:
+			err = ErrMaxAttempts
+			finished = true
+		default:
+			ended = false
+		}
+		mu.Unlock()
+	}
+	guard.check()
+
+	// the callbacks are done with the input and the output once finished is set.
+	key := endKey
+
+	if err != nil && timedOut {
+		err = ErrTimeout
+	} else if err != nil && ctx.Err() != nil {
+		err = &contextError{err: ctx.Err()}
+	} else if err != nil && exit {
+		err = ErrAbort
 	}
 
 	if err != nil {
@@ -213,52 +864,184 @@ func (p *Prompt) Run() (string, error) {
 			err = ErrInterrupt
 		}
 		sb.Reset()
-		sb.WriteString("")
-		sb.Flush()
-		rl.Write([]byte(showCursor))
+		if err == ErrMaxAttempts {
+			// the last value stays displayed with its error. It was not validated, so it is not trimmed.
+			sb.WriteLines(append(render(p.tpls.invalid, p.Label), p.echo(&cur)...))
+			sb.Write(render(p.tpls.validation, inputErr))
+		} else {
+			sb.WriteString("")
+		}
+		sb.FlushFinal()
+		if bracketedPaste {
+			rl.Write([]byte(disableBracketedPaste))
+		}
+		if !plain {
+			rl.Write([]byte(showCursor))
+		}
 		rl.Close()
-		return "", err
-	}
-
-	echo := cur.Format()
-	if p.Mask != 0 {
-		echo = cur.FormatMask(p.Mask)
+		if err == ErrMaxAttempts {
+			return PromptResult{Value: cur.Get(), Key: key}, err
+		}
+		if err == ErrTimeout {
+			return PromptResult{Value: p.trim(p.Default), Key: key}, err
+		}
+		if err == ErrEOF && p.EOFReturnsDefault {
+			return p.submitDefault(key)
+		}
+		return PromptResult{Key: key}, err
 	}
 
-	prompt := render(p.Templates.success, p.Label)
-	prompt = append(prompt, []byte(echo)...)
+	prompt := render(p.tpls.success, p.Label)
+	prompt = append(prompt, []byte(p.echo(&cur))...)
 
+	answered := true
 	if p.IsConfirm {
-		reg, regerr := regexp.Compile("[^A-Za-z0-9]+")
-		if regerr != nil {
-			return "", regerr
-		}
-		echoStripped := reg.ReplaceAllString(echo, "")
-
-		lowerDefault := strings.ToLower(p.Default)
-		lowerInput := strings.ToLower(string(echoStripped))
-		isNotY := strings.ToLower(cur.Get()) != "y" && (lowerDefault != "y" || (lowerDefault == "y" && cur.Get() != ""))
-		isNotYes := lowerInput != "yes" && (lowerDefault != "yes" || (lowerDefault == "yes" && lowerInput != ""))
-
-		if isNotY && isNotYes {
-			isNotN := strings.ToLower(cur.Get()) != "n" && (lowerDefault != "n" || (lowerDefault == "n" && cur.Get() != ""))
-			isNotNo := lowerInput != "no" && (lowerDefault != "no" || (lowerDefault == "no" && lowerInput != ""))
-			if isNotN && isNotNo {
-				prompt = render(p.Templates.invalid, p.Label)
-			}
+		yes, ok := confirmAnswer(cur.Get(), p.Default)
+		if !ok {
+			prompt = render(p.tpls.invalid, p.Label)
+		}
+		if !yes {
 			err = ErrAbort
 		}
+		answered = ok
+	}
+
+	if p.PlainSuccess {
+		prompt = screenbuf.StripANSI(prompt)
 	}
 
 	// Slight delay so prompt rendering does not conflict with listener
 	time.Sleep(50 * time.Millisecond)
 	sb.Reset()
-	sb.Write(prompt)
-	sb.Flush()
-	rl.Write([]byte(showCursor))
+	sb.WriteLines(prompt)
+	sb.FlushFinal()
+	value := p.trim(cur.Get())
+	if answered {
+		transcribe(p.TranscriptWriter, p.Label, p.answer(value))
+	}
+	if bracketedPaste {
+		rl.Write([]byte(disableBracketedPaste))
+	}
+	if !plain {
+		rl.Write([]byte(showCursor))
+	}
 	rl.Close()
 
-	return cur.Get(), err
+	return PromptResult{Value: value, Key: key}, err
+}
+
+// validate checks the value with ValidateContext, or with Validate if it is not set. The cursor is at the given
+// position in the value.
+func (p *Prompt) validate(value string, position int) error {
+	if p.ValidateContext != nil {
+		return p.ValidateContext(ValidationInput{
+			Value:     value,
+			Position:  position,
+			IsDefault: value == p.Default,
+			IsEmpty:   value == "",
+		})
+	}
+	if p.Validate != nil {
+		return p.Validate(value)
+	}
+	return nil
+}
+
+// trim returns the value without its leading and trailing white space when TrimSpace is set.
+func (p *Prompt) trim(value string) string {
+	if p.TrimSpace {
+		return strings.TrimSpace(value)
+	}
+	return value
+}
+
+// echo returns the input displayed after the label with the cursor, masked following the MaskMode when Mask is
+// set.
+func (p *Prompt) echo(cur *Cursor) string {
+	if p.Mask == 0 {
+		return cur.Format()
+	}
+
+	switch p.MaskMode {
+	case MaskFixed:
+		if len(cur.input) == 0 {
+			return string(cur.Cursor([]rune{}))
+		}
+		return strings.Repeat(string(p.Mask), MaskFixedLength) + string(cur.Cursor([]rune{}))
+	case MaskHidden:
+		return string(cur.Cursor([]rune{}))
+	}
+	return cur.FormatMask(p.Mask)
+}
+
+// answer returns the value as written to the TranscriptWriter, masked when Mask is set like it is displayed.
+func (p *Prompt) answer(value string) string {
+	if p.Mask == 0 {
+		return value
+	}
+
+	switch {
+	case p.MaskMode == MaskHidden, value == "":
+		return ""
+	case p.MaskMode == MaskFixed:
+		return strings.Repeat(string(p.Mask), MaskFixedLength)
+	}
+	return strings.Repeat(string(p.Mask), utf8.RuneCountInString(value))
+}
+
+// timeLeft returns the number of seconds left before the Timeout elapses, for the timeLeft template function.
+func (p *Prompt) timeLeft() int {
+	if p.Timeout <= 0 {
+		return 0
+	}
+
+	left := time.Until(p.deadline)
+	if left < 0 {
+		return 0
+	}
+	return int((left + time.Second - 1) / time.Second)
+}
+
+// isExitKey reports whether r is one of the ExitKeys of the prompt.
+func (p *Prompt) isExitKey(r rune) bool {
+	for _, k := range p.ExitKeys {
+		if r == k {
+			return true
+		}
+	}
+	return false
+}
+
+// errConfirmAnswer is displayed by RunConfirm when asking again after an invalid answer.
+var errConfirmAnswer = errors.New("please answer y or n")
+
+// confirmAnswer parses the answer to a confirm prompt, falling back to the default answer when the input is empty.
+// It returns whether the answer is yes and whether it is a valid answer at all.
+func confirmAnswer(input, def string) (yes bool, ok bool) {
+	answer := strings.ToLower(strings.TrimSpace(input))
+	if answer == "" {
+		answer = strings.ToLower(strings.TrimSpace(def))
+	}
+
+	switch answer {
+	case "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// PrepareTemplates compiles the templates of the prompt, and returns the error of an invalid template. The templates
+// are kept compiled for the next runs of the prompt, and Run only compiles them again once the template strings,
+// the FuncMap, the icons or the Default shaping the default Confirm template are replaced. Calling
+// PrepareTemplates checks them before the prompt is run, and compiles them again after functions were added to or
+// replaced in their FuncMap. Once compiled, the templates can be shared by prompts running at the same time.
+func (p *Prompt) PrepareTemplates() error {
+	if p.Templates != nil {
+		p.Templates.prompt = nil
+	}
+	return p.prepareTemplates()
 }
 
 func (p *Prompt) prepareTemplates() error {
@@ -271,29 +1054,62 @@ func (p *Prompt) prepareTemplates() error {
 		tpls.FuncMap = FuncMap
 	}
 
+	p.Templates = tpls
+
+	if tpls.prompt == nil || tpls.compiled != p.templatesKey(tpls) {
+		if err := p.compileTemplates(tpls); err != nil {
+			return err
+		}
+	}
+
+	p.tpls = tpls.promptTemplateSet.bind(p.stateFuncs())
+
+	return nil
+}
+
+// stateFuncs returns the template functions reading the state of the prompt while it runs.
+func (p *Prompt) stateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"timeLeft":  p.timeLeft,
+		"length":    func() int { return p.length },
+		"maxLength": func() int { return p.MaxLength },
+	}
+}
+
+// compileTemplates compiles the templates of tpls. The defaults of the empty template strings are not saved in
+// tpls, so they are compiled again for the icons and the Default of the next runs.
+func (p *Prompt) compileTemplates(tpls *PromptTemplates) error {
+	tpls.compiled = promptTemplatesKey{}
+
+	funcs := p.stateFuncs()
+	for name, fn := range withIcons(tpls.FuncMap, p.Icons) {
+		funcs[name] = fn
+	}
 	bold := Styler(FGBold)
 
 	if p.IsConfirm {
-		if tpls.Confirm == "" {
+		source := tpls.Confirm
+		if source == "" {
 			confirm := "y/N"
-			if strings.ToLower(p.Default) == "y" {
+			if p.confirmsByDefault() {
 				confirm = "Y/n"
 			}
-			tpls.Confirm = fmt.Sprintf(`{{ "%s" | bold }} {{ . | bold }}? {{ "[%s]" | faint }} `, IconInitial, confirm)
+			source = fmt.Sprintf(`{{ iconInitial | bold }} {{ . | bold }}? {{ "[%s]" | faint }} `, confirm)
 		}
 
-		tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Confirm)
+		tpl, err := template.New("").Funcs(funcs).Parse(source)
 		if err != nil {
 			return err
 		}
 
 		tpls.prompt = tpl
 	} else {
-		if tpls.Prompt == "" {
-			tpls.Prompt = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconInitial), bold(":"))
+		source := tpls.Prompt
+		if source == "" {
+			source = fmt.Sprintf("{{ iconInitial | bold }} {{ . | bold }}%s ", bold(":"))
 		}
 
-		tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Prompt)
+		tpl, err := template.New("").Funcs(funcs).Parse(source)
 		if err != nil {
 			return err
 		}
@@ -301,51 +1117,95 @@ func (p *Prompt) prepareTemplates() error {
 		tpls.prompt = tpl
 	}
 
-	if tpls.Valid == "" {
-		tpls.Valid = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconGood), bold(":"))
+	source := tpls.Valid
+	if source == "" {
+		source = fmt.Sprintf("{{ iconGood | bold }} {{ . | bold }}%s ", bold(":"))
 	}
 
-	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Valid)
+	tpl, err := template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.valid = tpl
 
-	if tpls.Invalid == "" {
-		tpls.Invalid = fmt.Sprintf("%s {{ . | bold }}%s ", bold(IconBad), bold(":"))
+	source = tpls.Invalid
+	if source == "" {
+		source = fmt.Sprintf("{{ iconBad | bold }} {{ . | bold }}%s ", bold(":"))
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Invalid)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.invalid = tpl
 
-	if tpls.ValidationError == "" {
-		tpls.ValidationError = `{{ ">>" | red }} {{ . | red }}`
+	source = tpls.ValidationError
+	if source == "" {
+		source = `{{ ">>" | red }} {{ . | red }}`
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.ValidationError)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.validation = tpl
 
-	if tpls.Success == "" {
-		tpls.Success = fmt.Sprintf("{{ . | faint }}%s ", Styler(FGFaint)(":"))
+	source = tpls.Success
+	if source == "" {
+		source = fmt.Sprintf("{{ . | faint }}%s ", Styler(FGFaint)(":"))
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Success)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.success = tpl
 
-	p.Templates = tpls
+	source = tpls.Suggestion
+	if source == "" {
+		source = `{{ if .Active }}{{ iconSelect }} {{ .Value | underline }}{{ else }}  {{ .Value | faint }}{{ end }}`
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+
+	tpls.suggestion = tpl
+
+	source = tpls.ConfirmLabel
+	if source == "" {
+		source = "Confirm"
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+
+	tpls.confirmLabel = tpl
+	tpls.compiled = p.templatesKey(tpls)
 
 	return nil
 }
+
+// confirmsByDefault reports whether the Default of a confirm prompt answers yes.
+func (p *Prompt) confirmsByDefault() bool {
+	return strings.ToLower(p.Default) == "y"
+}
+
+// templatesKey returns the key of the templates compiled from tpls for the prompt.
+func (p *Prompt) templatesKey(tpls *PromptTemplates) promptTemplatesKey {
+	return promptTemplatesKey{
+		isConfirm:  p.IsConfirm,
+		confirmYes: p.IsConfirm && p.confirmsByDefault(),
+		sources: [8]string{tpls.Prompt, tpls.Confirm, tpls.Valid, tpls.Invalid, tpls.Success, tpls.ValidationError,
+			tpls.Suggestion, tpls.ConfirmLabel},
+		funcMap: reflect.ValueOf(tpls.FuncMap).Pointer(),
+		icons:   activeIcons(p.Icons),
+	}
+}
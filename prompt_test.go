@@ -0,0 +1,1151 @@
+package promptui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestPromptTemplateRender(t *testing.T) {
+	t.Run("when rendering suggestions", func(t *testing.T) {
+		p := Prompt{Label: "File"}
+		err := p.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error preparing templates %v", err)
+		}
+
+		result := string(render(p.Templates.suggestion, PromptSuggestion{Value: "main.go", Active: true}))
+		exp := "\x1b[1m▸\x1b[0m \x1b[4mmain.go\x1b[0m"
+		if result != exp {
+			t.Errorf("Expected active suggestion to eq %q, got %q", exp, result)
+		}
+
+		result = string(render(p.Templates.suggestion, PromptSuggestion{Value: "main.go"}))
+		exp = "  \x1b[2mmain.go\x1b[0m"
+		if result != exp {
+			t.Errorf("Expected inactive suggestion to eq %q, got %q", exp, result)
+		}
+	})
+}
+
+func TestContextError(t *testing.T) {
+	err := error(&contextError{err: context.Canceled})
+
+	if !errors.Is(err, ErrAbort) {
+		t.Errorf("Expected %v to match ErrAbort", err)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v to match the context error", err)
+	}
+}
+
+func TestConfirmAnswer(t *testing.T) {
+	tcs := []struct {
+		input, def string
+		yes, ok    bool
+	}{
+		{"y", "", true, true},
+		{"Yes", "", true, true},
+		{"N", "y", false, true},
+		{" no ", "", false, true},
+		{"", "y", true, true},
+		{"", "N", false, true},
+		{"", "", false, false},
+		{"maybe", "y", false, false},
+	}
+
+	for _, tc := range tcs {
+		yes, ok := confirmAnswer(tc.input, tc.def)
+		if yes != tc.yes || ok != tc.ok {
+			t.Errorf("Expected %v, %v for %q with default %q, got %v, %v", tc.yes, tc.ok, tc.input, tc.def, yes, ok)
+		}
+	}
+}
+
+func TestIsExitKey(t *testing.T) {
+	p := Prompt{ExitKeys: []rune{KeyPrev, 'q'}}
+	if !p.isExitKey('q') || !p.isExitKey(KeyPrev) {
+		t.Error("Expected the exit keys to be recognized")
+	}
+	if p.isExitKey(KeyEnter) {
+		t.Error("Expected enter not to be an exit key")
+	}
+}
+
+func TestPromptRunResultKey(t *testing.T) {
+	tcs := []struct {
+		name  string
+		keys  []string
+		value string
+		key   rune
+		err   error
+	}{
+		{name: "enter", keys: []string{"Bell", "\r", "x", "\x03"}, value: "Bell", key: KeyEnter},
+		{name: "exit key", keys: []string{"Bell", "q", "\r"}, key: 'q', err: ErrAbort},
+		{name: "interrupt", keys: []string{"Bell", "\x03", "\r"}, key: 3, err: ErrInterrupt},
+		{name: "ctrl-d", keys: []string{"\x04", "\r"}, key: 4, err: ErrEOF},
+		{name: "input closed", keys: []string{"Bell"}, err: ErrEOF},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "Pepper", ExitKeys: []rune{'q'}, Terminal: term, Stdin: term, Stdout: term}
+
+			res, err := p.RunResult()
+			if res.Value != tc.value || res.Key != tc.key || err != tc.err {
+				t.Errorf("Expected %q, %d and %v, got %q, %d and %v", tc.value, tc.key, tc.err, res.Value, res.Key, err)
+			}
+		})
+	}
+}
+
+func TestPromptTimeLeft(t *testing.T) {
+	p := Prompt{Label: "Name", Templates: &PromptTemplates{Prompt: "{{ . }} ({{ timeLeft }}s)"}}
+	if left := p.timeLeft(); left != 0 {
+		t.Errorf("Expected no time left without timeout, got %d", left)
+	}
+
+	p.Timeout = 3 * time.Second
+	p.deadline = time.Now().Add(2500 * time.Millisecond)
+	if left := p.timeLeft(); left != 3 {
+		t.Errorf("Expected 3 seconds left, got %d", left)
+	}
+
+	err := p.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if label := string(render(p.Templates.prompt, p.Label)); label != "Name (3s)" {
+		t.Errorf("Expected %q, got %q", "Name (3s)", label)
+	}
+
+	p.deadline = time.Now().Add(-time.Second)
+	if left := p.timeLeft(); left != 0 {
+		t.Errorf("Expected no time left after the deadline, got %d", left)
+	}
+}
+
+func TestPromptRunWith(t *testing.T) {
+	var out bufferCloser
+	p := Prompt{
+		Label:  "Name",
+		Stdout: &out,
+		Validate: func(v string) error {
+			if v == "bad" {
+				return errors.New("invalid")
+			}
+			return nil
+		},
+	}
+
+	value, err := p.RunWith("value")
+	if err != nil || value != "value" {
+		t.Errorf("Expected value and no error, got %q and %v", value, err)
+	}
+	if exp := "Name: value\n"; !strings.HasSuffix(out.String(), exp) {
+		t.Errorf("Expected the success template in %q", out.String())
+	}
+
+	out.Reset()
+	if _, err := p.RunWith("bad"); err == nil || err.Error() != "invalid" {
+		t.Errorf("Expected the validation error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing displayed for an invalid value, got %q", out.String())
+	}
+
+	confirm := Prompt{Label: "Delete", IsConfirm: true, Stdout: &out}
+	if _, err := confirm.RunWith("n"); err != ErrAbort {
+		t.Errorf("Expected ErrAbort, got %v", err)
+	}
+}
+
+func TestPromptDelete(t *testing.T) {
+	term := NewTestTerminal("secret", "\x1b[H", "\x1b[C", "\x1b[3~", "\x1b[3~", "\r")
+	p := Prompt{Label: "Password", Mask: '*', Terminal: term, Stdin: term, Stdout: term}
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != "sret" {
+		t.Errorf("Expected %q, got %q", "sret", value)
+	}
+
+	out := term.Output()
+	if strings.Contains(out, "sret") || strings.Contains(out, "secret") {
+		t.Errorf("Expected the input to be masked, got %q", out)
+	}
+	if !strings.Contains(out, "*█**") {
+		t.Errorf("Expected the masked input to be drawn again with the cursor in place, got %q", out)
+	}
+}
+
+func TestPromptPlainSuccess(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(false)
+
+	term := NewTestTerminal("Bell", "\r")
+	p := Prompt{Label: "Pepper", PlainSuccess: true, Terminal: term, Stdin: term, Stdout: term}
+
+	if _, err := p.Run(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// readline may still clear the line below once the final line is written.
+	out := term.Output()
+	label := strings.LastIndex(out, "Pepper")
+	end := strings.Index(out[label+1:], "\n")
+	if label < 0 || end < 0 {
+		t.Fatalf("Expected a final line, got %q", out)
+	}
+	final := out[strings.LastIndex(out[:label], "\r")+1 : label+1+end]
+	if final != "Pepper: Bell█" {
+		t.Errorf("Expected the final line without styles, got %q", final)
+	}
+	if !strings.Contains(out, "\x1b[1mPepper") {
+		t.Errorf("Expected the prompt to be styled while typing, got %q", out)
+	}
+}
+
+func TestPromptPassword(t *testing.T) {
+	term := NewTestTerminal("\x1b[200~sec\x1b[201~", "ret", "\r")
+	p := Prompt{Label: "Password", Mask: '*', password: true, Terminal: term, Stdin: term, Stdout: term}
+
+	value, err := p.Run()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != "secret" {
+		t.Errorf("Expected %q, got %q", "secret", value)
+	}
+
+	out := term.Output()
+	if strings.Contains(out, "sec") || strings.Contains(out, "ret") {
+		t.Errorf("Expected the input to be masked, got %q", out)
+	}
+	if strings.Contains(out, enableBracketedPaste) || strings.Contains(out, disableBracketedPaste) {
+		t.Errorf("Expected the bracketed paste mode to be left disabled, got %q", out)
+	}
+	if !strings.Contains(out, "******") {
+		t.Errorf("Expected the masked input, got %q", out)
+	}
+}
+
+func TestPromptEOFReturnsDefault(t *testing.T) {
+	validate := func(v string) error {
+		if v == "bad" {
+			return errors.New("invalid")
+		}
+		return nil
+	}
+
+	tcs := []struct {
+		name    string
+		keys    []string
+		piped   bool
+		def     string
+		value   string
+		invalid bool
+	}{
+		{name: "input closed", def: "Bell", value: "Bell"},
+		{name: "ctrl-d", keys: []string{"\x04"}, def: "Bell", value: "Bell"},
+		{name: "piped input exhausted", piped: true, def: "Bell", value: "Bell"},
+		{name: "invalid default", def: "bad", value: "bad", invalid: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "Pepper", Default: tc.def, Validate: validate, EOFReturnsDefault: true, Stdout: term}
+			if tc.piped {
+				p.Stdin = ioutil.NopCloser(strings.NewReader(""))
+			} else {
+				p.Terminal, p.Stdin = term, term
+			}
+
+			res, err := p.RunResult()
+			switch {
+			case tc.invalid && (err == nil || err.Error() != "invalid"):
+				t.Errorf("Expected the validation error, got %v", err)
+			case !tc.invalid && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if res.Value != tc.value {
+				t.Errorf("Expected %q, got %q", tc.value, res.Value)
+			}
+			if !tc.invalid && !strings.HasSuffix(term.Output(), "Pepper: "+tc.value+"\n") {
+				t.Errorf("Expected the default to be submitted, got %q", term.Output())
+			}
+		})
+	}
+
+	p := Prompt{Label: "Pepper", Default: "Bell", Stdin: ioutil.NopCloser(strings.NewReader("")), Stdout: &bufferCloser{}}
+	if _, err := p.Run(); err != ErrEOF {
+		t.Errorf("Expected ErrEOF without EOFReturnsDefault, got %v", err)
+	}
+}
+
+func TestPromptTranscript(t *testing.T) {
+	tcs := []struct {
+		name    string
+		keys    []string
+		piped   string
+		mask    rune
+		confirm bool
+		twice   bool
+		exp     string
+	}{
+		{name: "typed", keys: []string{"Bell", "\r"}, exp: "Pepper: Bell\n"},
+		{name: "masked", keys: []string{"Bell", "\r"}, mask: '*', exp: "Pepper: ****\n"},
+		{name: "piped", piped: "Bell\n", exp: "Pepper: Bell\n"},
+		{name: "confirmed", keys: []string{"n", "\r"}, confirm: true, exp: "Pepper: n\n"},
+		{name: "interrupted", keys: []string{"Bell", "\x03"}},
+		{name: "input closed", keys: []string{"Bell"}},
+		{name: "entered twice", keys: []string{"Bell", "\r", "Bel", "\r", "Bell", "\r", "Bell", "\r"}, mask: '*',
+			twice: true, exp: "Pepper: ****\n"},
+		{name: "entered twice differently", keys: []string{"Bell", "\r", "Bel", "\r", "Bell", "\r", "Be", "\r"},
+			mask: '*', twice: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var transcript bytes.Buffer
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "Pepper", Mask: tc.mask, IsConfirm: tc.confirm, Confirm: tc.twice, ConfirmRetries: 1,
+				TranscriptWriter: &transcript, Stdout: term}
+			if tc.piped != "" {
+				p.Stdin = ioutil.NopCloser(strings.NewReader(tc.piped))
+			} else {
+				p.Terminal, p.Stdin = term, term
+			}
+
+			p.Run()
+			if transcript.String() != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, transcript.String())
+			}
+		})
+	}
+}
+
+func TestPromptRedraw(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	term := NewTestTerminal("Bell", "\x1b[D", "\x0c")
+	var screen []string
+	in := &pausedReader{TestTerminal: term, done: func() {
+		out := term.Output()
+		screen = emulate(out[strings.LastIndex(out, "\x1b[2J\x1b[H"):])
+	}}
+
+	p := Prompt{Label: "Pepper", Terminal: term, Stdin: ioutil.NopCloser(in), Stdout: term}
+	if _, err := p.Run(); err != ErrEOF {
+		t.Fatalf("Expected ErrEOF, got %v", err)
+	}
+
+	exp := []string{"✔ Pepper: Bel█"}
+	if !reflect.DeepEqual(screen, exp) {
+		t.Errorf("Expected the prompt drawn again with its input and cursor %q, got %q", exp, screen)
+	}
+}
+
+func TestPromptPanic(t *testing.T) {
+	tcs := []struct {
+		name   string
+		prompt Prompt
+	}{
+		{name: "validator", prompt: Prompt{Validate: func(input string) error {
+			if input == "boom" {
+				panic("boom")
+			}
+			return nil
+		}}},
+		{name: "key handler", prompt: Prompt{KeyHandler: func(r rune, input []rune) (bool, []rune) {
+			if r == 'm' {
+				panic("boom")
+			}
+			return false, input
+		}}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal("boom", "\r")
+			p := tc.prompt
+			p.Label = "Pepper"
+			p.Terminal, p.Stdin, p.Stdout = term, term, term
+
+			func() {
+				defer func() {
+					if v := recover(); v != "boom" {
+						t.Errorf("Expected the panic to be raised again, got %v", v)
+					}
+				}()
+				p.Run()
+			}()
+
+			if term.IsRaw() {
+				t.Errorf("Expected the terminal to be restored")
+			}
+			if out := term.Output(); !strings.Contains(out, showCursor) || !strings.Contains(out, disableBracketedPaste) {
+				t.Errorf("Expected the cursor to be shown and the bracketed paste disabled, got %q", out)
+			}
+		})
+	}
+}
+
+func TestPromptTrimSpace(t *testing.T) {
+	tcs := []struct {
+		name     string
+		keys     string
+		piped    bool
+		trim     bool
+		expected string
+	}{
+		{name: "typed", keys: "  Bell Pepper  ", expected: "  Bell Pepper  "},
+		{name: "typed trimmed", keys: "  Bell Pepper  ", trim: true, expected: "Bell Pepper"},
+		{name: "default", expected: " Habanero "},
+		{name: "default trimmed", trim: true, expected: "Habanero"},
+		{name: "piped", keys: " Jalapeno ", piped: true, expected: " Jalapeno "},
+		{name: "piped trimmed", keys: " Jalapeno ", piped: true, trim: true, expected: "Jalapeno"},
+		{name: "piped default trimmed", piped: true, trim: true, expected: "Habanero"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Prompt{Label: "Pepper", Default: " Habanero ", TrimSpace: tc.trim}
+			if tc.piped {
+				p.Stdin = ioutil.NopCloser(strings.NewReader(tc.keys + "\n"))
+				p.Stdout = &bufferCloser{}
+			} else {
+				term := NewTestTerminal(tc.keys, "\r")
+				p.Terminal, p.Stdin, p.Stdout = term, term, term
+			}
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if value != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestPromptMaxLength(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	tcs := []struct {
+		name     string
+		keys     []string
+		def      string
+		expected string
+		counter  string
+	}{
+		{name: "typed", keys: []string{"Habanero", "\r"}, expected: "Haba", counter: "Pepper (4/4)"},
+		{name: "edited", keys: []string{"Habanero", "\x7f", "\x7f", "nero", "\r"}, expected: "Hane", counter: "Pepper (4/4)"},
+		{name: "default erased", keys: []string{"Jalapeno", "\r"}, def: "Bell", expected: "Jala", counter: "Pepper (4/4)"},
+		{name: "short", keys: []string{"Ha", "\r"}, expected: "Ha", counter: "Pepper (2/4)"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{
+				Label:     "Pepper",
+				Default:   tc.def,
+				MaxLength: 4,
+				Templates: &PromptTemplates{Valid: "{{ . }} ({{ length }}/{{ maxLength }}) "},
+				Terminal:  term,
+				Stdin:     term,
+				Stdout:    term,
+			}
+
+			value, err := p.Run()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if value != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, value)
+			}
+			if out := term.Output(); !strings.Contains(out, tc.counter) {
+				t.Errorf("Expected the counter %q, got %q", tc.counter, out)
+			}
+		})
+	}
+}
+
+func TestPromptBell(t *testing.T) {
+	tcs := []struct {
+		name     string
+		prompt   Prompt
+		keys     string
+		expected int
+	}{
+		{name: "filtered", prompt: Prompt{Bell: true, InputFilter: FilterDigits}, keys: "1a2b", expected: 2},
+		{name: "max length", prompt: Prompt{Bell: true, MaxLength: 2}, keys: "123", expected: 1},
+		{name: "accepted", prompt: Prompt{Bell: true, MaxLength: 3}, keys: "123", expected: 0},
+		{name: "disabled", prompt: Prompt{MaxLength: 2}, keys: "123", expected: 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys, "\r")
+			p := tc.prompt
+			p.Label = "Pepper"
+			p.Terminal, p.Stdin, p.Stdout = term, term, term
+
+			if _, err := p.Run(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if n := strings.Count(term.Output(), bell); n != tc.expected {
+				t.Errorf("Expected the bell rung %d times, got %d", tc.expected, n)
+			}
+		})
+	}
+}
+
+func TestPromptMaskMode(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	tcs := []struct {
+		name     string
+		mode     MaskMode
+		keys     []string
+		expected []string
+	}{
+		{name: "per char", keys: []string{"secret"}, expected: []string{"✔ Password: ******█"}},
+		{name: "fixed", mode: MaskFixed, keys: []string{"secret"}, expected: []string{"✔ Password: ********█"}},
+		{name: "fixed empty", mode: MaskFixed, expected: []string{"✔ Password: █"}},
+		{name: "hidden", mode: MaskHidden, keys: []string{"secret"}, expected: []string{"✔ Password: █"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			p := Prompt{
+				Label:    "Password",
+				Mask:     '*',
+				MaskMode: tc.mode,
+				Terminal: term,
+				Stdin:    ioutil.NopCloser(in),
+				Stdout:   term,
+			}
+			p.Run()
+
+			if !reflect.DeepEqual(screen, tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, screen)
+			}
+		})
+	}
+
+	t.Run("hidden edited", func(t *testing.T) {
+		term := NewTestTerminal("secret", "\x1b[D", "\x1b[D", "\x7f", "\r")
+		p := Prompt{Label: "Password", Mask: '*', MaskMode: MaskHidden, Terminal: term, Stdin: term, Stdout: term}
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if value != "secet" {
+			t.Errorf("Expected the input to be edited as usual, got %q", value)
+		}
+	})
+}
+
+func TestPromptValidateContext(t *testing.T) {
+	tcs := []struct {
+		name  string
+		keys  []string
+		value string
+		input ValidationInput
+	}{
+		{name: "unchanged default", keys: []string{"\r"}, value: "Habanero",
+			input: ValidationInput{Value: "Habanero", Position: 8, IsDefault: true}},
+		// the empty value submitted first is refused.
+		{name: "required", keys: []string{"\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f", "\r", "Bell", "\x1b[D", "\r"},
+			value: "Bell", input: ValidationInput{Value: "Bell", Position: 3}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var input ValidationInput
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{
+				Label:     "Pepper",
+				Default:   "Habanero",
+				AllowEdit: true,
+				Validate:  func(string) error { return errors.New("not called") },
+				ValidateContext: func(in ValidationInput) error {
+					input = in
+					if in.IsEmpty && !in.IsDefault {
+						return errors.New("required")
+					}
+					return nil
+				},
+				Terminal: term,
+				Stdin:    term,
+				Stdout:   term,
+			}
+
+			value, err := p.Run()
+			if value != tc.value || err != nil {
+				t.Errorf("Expected %q and no error, got %q and %v", tc.value, value, err)
+			}
+			if input != tc.input {
+				t.Errorf("Expected the input %+v, got %+v", tc.input, input)
+			}
+		})
+	}
+
+	p := Prompt{Label: "Pepper", Default: "Habanero", Stdout: &bufferCloser{},
+		ValidateContext: func(in ValidationInput) error {
+			if !in.IsDefault {
+				return fmt.Errorf("%q at %d is not the default", in.Value, in.Position)
+			}
+			return nil
+		}}
+	if _, err := p.RunWith("Jalapeño"); err == nil || err.Error() != `"Jalapeño" at 8 is not the default` {
+		t.Errorf("Expected the validation error, got %v", err)
+	}
+}
+
+func TestPromptMaxAttempts(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	validate := func(input string) error {
+		if _, err := strconv.Atoi(input); err != nil {
+			return errors.New("not a number")
+		}
+		return nil
+	}
+
+	tcs := []struct {
+		name   string
+		max    int
+		keys   []string
+		value  string
+		err    error
+		screen []string
+	}{
+		{name: "valid", max: 2, keys: []string{"ab", "\r", "\x7f\x7f", "42", "\r"}, value: "42",
+			screen: []string{"Age: 42█"}},
+		{name: "reached", max: 2, keys: []string{"ab", "\r", "cd", "\r"}, value: "abcd", err: ErrMaxAttempts,
+			screen: []string{"✗ Age: abcd█", ">> not a number"}},
+		{name: "once", max: 1, keys: []string{"ab", "\r"}, value: "ab", err: ErrMaxAttempts,
+			screen: []string{"✗ Age: ab█", ">> not a number"}},
+		{name: "unlimited", keys: []string{"ab", "\r", "cd", "\r"}, err: ErrEOF},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{
+				Label:       "Age",
+				Validate:    validate,
+				MaxAttempts: tc.max,
+				Terminal:    term,
+				Stdin:       term,
+				Stdout:      term,
+			}
+
+			value, err := p.Run()
+			if value != tc.value || err != tc.err {
+				t.Errorf("Expected %q and %v, got %q and %v", tc.value, tc.err, value, err)
+			}
+			if screen := emulate(term.Output()); tc.screen != nil && !reflect.DeepEqual(screen, tc.screen) {
+				t.Errorf("Expected %q, got %q", tc.screen, screen)
+			}
+		})
+	}
+}
+
+func TestPromptPrepareTemplates(t *testing.T) {
+	p := Prompt{Label: "Pepper", Templates: &PromptTemplates{Prompt: "{{ . }}: "}}
+	if err := p.PrepareTemplates(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	compiled := p.Templates.prompt
+
+	if err := p.prepareTemplates(); err != nil || p.Templates.prompt != compiled {
+		t.Errorf("Expected the templates kept compiled, got %v", err)
+	}
+
+	p.Templates.Prompt = "{{ . | bold }}: "
+	if err := p.prepareTemplates(); err != nil || p.Templates.prompt == compiled {
+		t.Errorf("Expected the changed template compiled again, got %v", err)
+	}
+	compiled = p.Templates.prompt
+
+	p.Icons = &IconSet{Good: "+", Bad: "-"}
+	if err := p.prepareTemplates(); err != nil || p.Templates.prompt == compiled {
+		t.Errorf("Expected the templates compiled again with the icons, got %v", err)
+	}
+	compiled = p.Templates.prompt
+
+	// a copy shares the compiled templates, and their template functions read the prompt running with them.
+	c := p
+	c.MaxLength = 8
+	c.Templates.Prompt = "{{ maxLength }}: "
+	if err := c.prepareTemplates(); err != nil || c.Templates.prompt == compiled {
+		t.Errorf("Expected the changed template compiled again, got %v", err)
+	}
+	compiled = c.Templates.prompt
+
+	if err := p.prepareTemplates(); err != nil || p.Templates.prompt != compiled {
+		t.Errorf("Expected the templates kept compiled for the prompt copied, got %v", err)
+	}
+	if err := c.prepareTemplates(); err != nil || c.Templates.prompt != compiled {
+		t.Errorf("Expected the templates kept compiled for the copy, got %v", err)
+	}
+	if got := string(render(c.tpls.prompt, c.Label)); got != "8: " {
+		t.Errorf("Expected %q, got %q", "8: ", got)
+	}
+	if got := string(render(p.tpls.prompt, p.Label)); got != "0: " {
+		t.Errorf("Expected %q, got %q", "0: ", got)
+	}
+
+	if err := c.PrepareTemplates(); err != nil || c.Templates.prompt == compiled {
+		t.Errorf("Expected PrepareTemplates to compile the templates again, got %v", err)
+	}
+
+	p.Templates.Prompt = "{{ . | unknown }}"
+	if err := p.PrepareTemplates(); err == nil {
+		t.Error("Expected the error of the invalid template")
+	}
+}
+
+func TestPromptFuncMapChanged(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	funcs := template.FuncMap{"shout": strings.ToUpper}
+	for name, fn := range FuncMap {
+		funcs[name] = fn
+	}
+	p := Prompt{Label: "Pepper", Templates: &PromptTemplates{Success: "{{ . | shout }}: ", FuncMap: funcs}}
+
+	run := func(exp string) {
+		t.Helper()
+
+		term := NewTestTerminal("Bell", "\r")
+		p.Terminal, p.Stdin, p.Stdout = term, term, term
+		if _, err := p.Run(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if screen := emulate(term.Output()); !strings.Contains(strings.Join(screen, "\n"), exp) {
+			t.Errorf("Expected %q in the output, got %q", exp, screen)
+		}
+	}
+
+	run("PEPPER: Bell")
+
+	funcs["shout"] = func(s string) string { return s + "!" }
+	if err := p.PrepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	run("Pepper!: Bell")
+
+	// a function added in place is used by a template changed to call it.
+	funcs["whisper"] = strings.ToLower
+	p.Templates.Success = "{{ . | whisper }}: "
+	run("pepper: Bell")
+}
+
+func TestPromptDefaultChanged(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	p := Prompt{Label: "Pepper", IsConfirm: true}
+	for _, tc := range []struct{ def, hint string }{{"n", "[y/N]"}, {"y", "[Y/n]"}} {
+		term := NewTestTerminal("\r")
+		p.Default, p.Terminal, p.Stdin, p.Stdout = tc.def, term, term, term
+		p.Run()
+		if !strings.Contains(term.Output(), tc.hint) {
+			t.Errorf("Expected %q in the output, got %q", tc.hint, term.Output())
+		}
+	}
+}
+
+func TestPromptSharedTemplates(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	tpls := &PromptTemplates{Prompt: "{{ . }} ({{ length }}): ", Success: "{{ . }} ({{ length }}): "}
+	if err := (&Prompt{Templates: tpls}).PrepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, value := range []string{"Bell", "Jalapeno", "Jal"} {
+		wg.Add(1)
+		go func(value string) {
+			defer wg.Done()
+
+			term := NewTestTerminal(value, "\r")
+			p := Prompt{Label: "Pepper", Templates: tpls, Terminal: term, Stdin: term, Stdout: term}
+			if _, err := p.Run(); err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+			exp := fmt.Sprintf("Pepper (%d): %s", len(value), value)
+			if screen := emulate(term.Output()); !strings.Contains(strings.Join(screen, "\n"), exp) {
+				t.Errorf("Expected %q in the output, got %q", exp, screen)
+			}
+		}(value)
+	}
+	wg.Wait()
+}
+
+// BenchmarkPromptTemplates prepares the templates of a prompt run repeatedly, already compiled, shared with a
+// copy like the one asking for a Confirm value, or parsed again each time.
+func BenchmarkPromptTemplates(b *testing.B) {
+	for _, name := range []string{"compiled", "copied", "parsed"} {
+		b.Run(name, func(b *testing.B) {
+			p := Prompt{Label: "Pepper"}
+			if err := p.PrepareTemplates(); err != nil {
+				b.Fatalf("Unexpected error preparing the templates %v", err)
+			}
+			confirm := p
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				prepare := p.prepareTemplates
+				switch {
+				case name == "copied" && i%2 == 1:
+					prepare = confirm.prepareTemplates
+				case name == "parsed":
+					prepare = p.PrepareTemplates
+				}
+				if err := prepare(); err != nil {
+					b.Fatalf("Unexpected error preparing the templates %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptTimeout(t *testing.T) {
+	term := NewTestTerminal("Jal")
+	in := newOpenReader(term)
+	defer in.Close()
+
+	p := Prompt{
+		Label:    "Pepper",
+		Default:  "Bell",
+		Timeout:  200 * time.Millisecond,
+		Terminal: term,
+		Stdin:    in,
+		Stdout:   term,
+	}
+
+	start := time.Now()
+	value, err := p.Run()
+	if value != "Bell" || err != ErrTimeout {
+		t.Errorf("Expected %q and ErrTimeout, got %q and %v", "Bell", value, err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected the prompt to wait for the timeout after the last key, took %v", elapsed)
+	}
+	if term.IsRaw() {
+		t.Error("Expected the terminal restored")
+	}
+}
+
+func TestPromptConfirm(t *testing.T) {
+	tcs := []struct {
+		name    string
+		keys    []string
+		initial string
+		retries int
+		value   string
+		err     error
+	}{
+		{name: "match", keys: []string{"secret", "\r", "secret", "\r"}, value: "secret"},
+		{name: "initial value", keys: []string{"\r", "secret", "\r"}, initial: "secret", value: "secret"},
+		{name: "mismatch then match", keys: []string{"secret", "\r", "secrte", "\r", "other", "\r", "other", "\r"},
+			retries: 1, value: "other"},
+		{name: "retries exhausted", keys: []string{"secret", "\r", "secrte", "\r", "other", "\r", "otter", "\r"},
+			retries: 1, err: ErrMismatch},
+		{name: "no retry", keys: []string{"secret", "\r", "secrte", "\r", "secret", "\r"}, err: ErrMismatch},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{
+				Label:          "Password",
+				Mask:           '*',
+				Confirm:        true,
+				ConfirmRetries: tc.retries,
+				InitialValue:   tc.initial,
+				Templates:      &PromptTemplates{ConfirmLabel: "{{ . }} again"},
+				Terminal:       term,
+				Stdin:          term,
+				Stdout:         term,
+			}
+
+			value, err := p.Run()
+			if value != tc.value || err != tc.err {
+				t.Errorf("Expected %q and %v, got %q and %v", tc.value, tc.err, value, err)
+			}
+			if !strings.Contains(term.Output(), "Password again") {
+				t.Errorf("Expected the confirm label rendered by its template, got %q", term.Output())
+			}
+		})
+	}
+}
+
+func TestPromptValidateLive(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	tcs := []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{name: "invalid", keys: []string{"4", "2a"},
+			expected: []string{IconBad + " Age: 42a█", ">> not a number"}},
+		{name: "corrected", keys: []string{"4", "2a", "\x7f"},
+			expected: []string{IconGood + " Age: 42█"}},
+	}
+
+	validate := func(input string) error {
+		if _, err := strconv.Atoi(input); err != nil {
+			return errors.New("not a number")
+		}
+		return nil
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			p := Prompt{
+				Label:        "Age",
+				Validate:     validate,
+				ValidateLive: true,
+				Terminal:     term,
+				Stdin:        ioutil.NopCloser(in),
+				Stdout:       term,
+			}
+			if _, err := p.Run(); err != ErrEOF {
+				t.Fatalf("Expected ErrEOF, got %v", err)
+			}
+
+			if !reflect.DeepEqual(screen, tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, screen)
+			}
+		})
+	}
+
+	t.Run("submitted", func(t *testing.T) {
+		term := NewTestTerminal("4", "2a", "\x7f", "\r")
+		p := Prompt{Label: "Age", Validate: validate, ValidateLive: true, Terminal: term, Stdin: term, Stdout: term}
+
+		value, err := p.Run()
+		if value != "42" || err != nil {
+			t.Errorf("Expected %q and no error, got %q and %v", "42", value, err)
+		}
+	})
+}
+
+func TestPromptInitialValue(t *testing.T) {
+	tcs := []struct {
+		name  string
+		keys  []string
+		piped string
+		value string
+	}{
+		{name: "submitted", keys: []string{"\r"}, value: "Habanero"},
+		{name: "edited", keys: []string{"\x7f\x7f\x7f\x7f", "na", "\r"}, value: "Habana"},
+		{name: "cleared", keys: []string{"\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f", "\r"}, value: ""},
+		{name: "piped empty line", piped: "\n", value: "Habanero"},
+		{name: "piped line", piped: "Jalapeno\n", value: "Jalapeno"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Prompt{Label: "Pepper", Default: "Bell", InitialValue: "Habanero"}
+			if tc.piped != "" {
+				p.Stdin = ioutil.NopCloser(strings.NewReader(tc.piped))
+				p.Stdout = &bufferCloser{}
+			} else {
+				term := NewTestTerminal(tc.keys...)
+				p.Terminal, p.Stdin, p.Stdout = term, term, term
+			}
+
+			value, err := p.Run()
+			if value != tc.value || err != nil {
+				t.Errorf("Expected %q and no error, got %q and %v", tc.value, value, err)
+			}
+		})
+	}
+}
+
+func TestPromptHistoryRun(t *testing.T) {
+	up, down := string(KeyPrev), string(KeyNext)
+	tcs := []struct {
+		name  string
+		keys  []string
+		value string
+	}{
+		{name: "last entry", keys: []string{up, "\r"}, value: "Habanero"},
+		{name: "older entry", keys: []string{up, up, "\r"}, value: "Jalapeno"},
+		{name: "past the first entry", keys: []string{up, up, up, up, "\r"}, value: "Bell"},
+		{name: "back to the input", keys: []string{"Ser", up, up, down, down, "\r"}, value: "Ser"},
+		{name: "past the input", keys: []string{"Ser", down, "\r"}, value: "Ser"},
+		{name: "edited entry", keys: []string{up, up, "\x7f\x7f", "ia", "\r"}, value: "Jalapeia"},
+		{name: "edited entry then down", keys: []string{"Ser", up, up, "\x7f\x7f", "ia", down, "\r"}, value: "Habanero"},
+		{name: "edited entry then back to the input", keys: []string{"Ser", up, "\x7f", down, "\r"}, value: "Ser"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			history := []string{"Bell", "Jalapeno", "Habanero"}
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "Pepper", History: history, Terminal: term, Stdin: term, Stdout: term}
+
+			value, err := p.Run()
+			if value != tc.value || err != nil {
+				t.Errorf("Expected %q and no error, got %q and %v", tc.value, value, err)
+			}
+			if !reflect.DeepEqual(history, []string{"Bell", "Jalapeno", "Habanero"}) {
+				t.Errorf("Expected the history unchanged, got %q", history)
+			}
+		})
+	}
+}
+
+func TestPromptSuggestRun(t *testing.T) {
+	files := []string{"main.go", "make", "README.md"}
+	suggest := func(input string) []string {
+		var found []string
+		for _, f := range files {
+			if strings.HasPrefix(f, input) {
+				found = append(found, f)
+			}
+		}
+		return found
+	}
+
+	tcs := []struct {
+		name  string
+		keys  []string
+		value string
+	}{
+		{name: "single suggestion", keys: []string{"R", "\t", "\r"}, value: "README.md"},
+		{name: "suggestions listed", keys: []string{"ma", "\t", "\r"}, value: "ma"},
+		{name: "first suggestion", keys: []string{"ma", "\t", "\t", "\r"}, value: "main.go"},
+		{name: "next suggestion", keys: []string{"ma", "\t", "\t", "\t", "\r"}, value: "make"},
+		{name: "cycled back", keys: []string{"ma", "\t", "\t", "\t", "\t", "\r"}, value: "main.go"},
+		// the suggestions are listed again for the edited input instead of being cycled.
+		{name: "cycle cleared by typing", keys: []string{"ma", "\t", "\t", "\x7f\x7f\x7f\x7f\x7f", "\t", "\r"}, value: "ma"},
+		{name: "no suggestion", keys: []string{"x", "\t", "\r"}, value: "x"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "File", Suggest: suggest, Terminal: term, Stdin: term, Stdout: term}
+
+			value, err := p.Run()
+			if value != tc.value || err != nil {
+				t.Errorf("Expected %q and no error, got %q and %v", tc.value, value, err)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		defer DisableColors(colorsDisabled)
+		DisableColors(true)
+
+		tcs := []struct {
+			keys []string
+			exp  []string
+		}{
+			{keys: []string{"ma", "\t", "\t"}, exp: []string{"✔ File: main.go█", "▸ main.go", "  make"}},
+			{keys: []string{"ma", "\t", "\t", "s"}, exp: []string{"✔ File: main.gos█"}},
+		}
+
+		for _, tc := range tcs {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			p := Prompt{Label: "File", Suggest: suggest, Terminal: term, Stdin: ioutil.NopCloser(in), Stdout: term}
+			p.Run()
+
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		}
+	})
+}
+
+func TestPromptSubmitKey(t *testing.T) {
+	tcs := []struct {
+		name  string
+		keys  []string
+		value string
+		key   rune
+		err   error
+	}{
+		{name: "submitted", keys: []string{"Bell", "\r", "Jalapeno", "\x13"}, value: "Bell\nJalapeno", key: '\x13'},
+		{name: "enter inserting lines", keys: []string{"Bell", "\r", "\r", "\x13"}, value: "Bell\n\n", key: '\x13'},
+		{name: "enter not submitting", keys: []string{"Bell", "\r"}, err: ErrEOF},
+		{name: "empty line", keys: []string{"Bell", "\r", "\x04"}, value: "Bell\n", key: '\x04'},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			p := Prompt{Label: "Peppers", Multiline: true, SubmitKey: '\x13', Terminal: term, Stdin: term, Stdout: term}
+
+			res, err := p.RunResult()
+			if res.Value != tc.value || res.Key != tc.key || err != tc.err {
+				t.Errorf("Expected %q, key %d and %v, got %q, key %d and %v", tc.value, tc.key, tc.err, res.Value,
+					res.Key, err)
+			}
+		})
+	}
+}
+
+func TestPromptRedrawUnchanged(t *testing.T) {
+	term := NewTestTerminal("B", "e", "l", "l", "\r")
+	p := Prompt{
+		Label:        "Pepper",
+		Validate:     func(input string) error { return errors.New("unknown pepper") },
+		ValidateLive: true,
+		Terminal:     term,
+		Stdin:        term,
+		Stdout:       term,
+	}
+	p.Run()
+
+	// the validation error below the input is written once, and skipped by the frames drawn for the next keys.
+	if n := strings.Count(term.Output(), "unknown pepper"); n != 1 {
+		t.Errorf("Expected the validation error written once, got %d times in %q", n, term.Output())
+	}
+}
@@ -10,7 +10,17 @@
 // detailed view and custom templates.
 package promptui
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"text/template"
+
+	"github.com/chzyer/readline"
+	"github.com/logrhythm/promptui/screenbuf"
+)
 
 // ErrEOF is the error returned from prompts when EOF is encountered.
 var ErrEOF = errors.New("^D")
@@ -22,6 +32,162 @@ var ErrInterrupt = errors.New("^C")
 // ErrAbort is the error returned when confirm prompts are supplied "n"
 var ErrAbort = errors.New("")
 
+// ErrMismatch is the error returned when the two values entered in a prompt with Confirm set are different.
+var ErrMismatch = errors.New("values do not match")
+
+// ErrTimeout is the error returned from prompts when their Timeout elapses without any key being pressed.
+var ErrTimeout = errors.New("timeout")
+
+// ErrMaxAttempts is the error returned from prompts when the value is still invalid after MaxAttempts submissions.
+var ErrMaxAttempts = errors.New("too many invalid attempts")
+
+// ErrNoMatch is the error returned from selects reading their input from a pipe when no item matches it.
+var ErrNoMatch = errors.New("no item matches the input")
+
+// ErrEmptyList is the error returned from selects without any item to select, before anything is displayed.
+var ErrEmptyList = errors.New("no item to select")
+
 // ValidateFunc is a placeholder type for any validation functions that validates a given input. It should return
 // a ValidationError if the input is not valid.
 type ValidateFunc func(string) error
+
+// ValidationInput is the value checked by the ValidateContext function of a prompt, with the state of the
+// prompt it was entered in.
+type ValidationInput struct {
+	// Value is the entered value.
+	Value string
+
+	// Position is the position of the cursor in Value, in runes. It is at the end of the values that were not
+	// typed, like a piped value or the value of RunWith.
+	Position int
+
+	// IsDefault reports whether Value equals the Default of the prompt, like a default submitted unchanged.
+	IsDefault bool
+
+	// IsEmpty reports whether Value is empty.
+	IsEmpty bool
+}
+
+// contextError is returned when a prompt stops waiting for input because its context is done. It wraps the
+// context error and matches ErrAbort when using errors.Is.
+type contextError struct {
+	err error
+}
+
+func (e *contextError) Error() string {
+	return "aborted: " + e.err.Error()
+}
+
+func (e *contextError) Unwrap() error {
+	return e.err
+}
+
+func (e *contextError) Is(target error) bool {
+	return target == ErrAbort
+}
+
+// isTerminal reports whether the reader or writer v is a terminal. Values without a file descriptor, like buffers,
+// are not terminals.
+func isTerminal(v interface{}) bool {
+	f, ok := v.(interface{ Fd() uintptr })
+	return ok && readline.IsTerminal(int(f.Fd()))
+}
+
+// closeOnDone closes c once the context is done, which unblocks any pending read. It stops watching the
+// context when the done channel is closed.
+func closeOnDone(ctx context.Context, done <-chan struct{}, c io.Closer) {
+	if ctx.Done() == nil {
+		return
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+}
+
+// bindTemplate returns a copy of tpl calling the functions of funcs instead of its functions of the same names, so
+// templates compiled once can render the state of each prompt or select running with them.
+func bindTemplate(tpl *template.Template, funcs template.FuncMap) *template.Template {
+	if tpl == nil {
+		return nil
+	}
+	return template.Must(tpl.Clone()).Funcs(funcs)
+}
+
+// closerFunc turns a function into an io.Closer.
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// panicGuard leaves the terminal usable when a function of the caller, like Validate or a template, panics while
+// a prompt or a select runs. The panic is raised again once the terminal is restored, so its stack trace is
+// printed on a terminal out of raw mode, with its cursor shown.
+//
+// Readline calls the listener and the key filter on its own goroutines, where a panic cannot be recovered by Run.
+// These panics are caught by catch and raised again on the goroutine of Run by check, with the stack of Run.
+type panicGuard struct {
+	rl *readline.Instance
+
+	// reset holds the escape codes written to restore the terminal, like showCursor.
+	reset string
+
+	mu     sync.Mutex
+	value  interface{}
+	caught bool
+}
+
+// catch recovers a panic of a function called by readline, and closes readline so Run stops waiting for input.
+// It must be deferred directly by the function.
+func (g *panicGuard) catch() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.caught {
+		g.value, g.caught = v, true
+		// readline waits for its goroutines to return when closed, including the one calling this function.
+		go g.rl.Close()
+	}
+}
+
+// check raises again a panic caught by catch.
+func (g *panicGuard) check() {
+	g.mu.Lock()
+	v, caught := g.value, g.caught
+	g.mu.Unlock()
+
+	if caught {
+		panic(v)
+	}
+}
+
+// restore restores the terminal and raises the panic again if Run is panicking. It must be deferred directly by
+// Run once readline is created.
+func (g *panicGuard) restore() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	g.rl.Write([]byte(g.reset + "\n"))
+	g.rl.Close()
+	panic(v)
+}
+
+// transcribe appends the line "label: answer" to the TranscriptWriter w of a completed prompt or select, without
+// the styles. Nothing is written when w is nil.
+func transcribe(w io.Writer, label interface{}, answer string) {
+	if w == nil {
+		return
+	}
+	w.Write(screenbuf.StripANSI([]byte(fmt.Sprintf("%v: %s\n", label, answer))))
+}
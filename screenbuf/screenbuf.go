@@ -5,21 +5,40 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strings"
 	"unicode/utf8"
 
 	terminal "github.com/wayneashleyberry/terminal-dimensions"
 )
 
+// DefaultWidth is the terminal width assumed when the actual width cannot be
+// detected.
+const DefaultWidth = 80
+
+// DefaultHeight is the terminal height assumed when the actual height cannot
+// be detected.
+const DefaultHeight = 24
+
+// DefaultCursorColumns is the number of columns reserved for the input cursor
+// at the end of prompt lines.
+const DefaultCursorColumns = 2
+
+// DefaultTabWidth is the number of columns between two tab stops.
+const DefaultTabWidth = 8
+
 const (
 	esc  = "\033["
 	ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 )
 
 var (
-	clearLine = []byte(esc + "2K\r")
-	moveUp    = []byte(esc + "1A")
-	moveDown  = []byte(esc + "1B")
-	re        = regexp.MustCompile(ansi)
+	clearLine     = []byte(esc + "2K\r")
+	clearScreen   = []byte(esc + "2J" + esc + "H")
+	moveUp        = []byte(esc + "1A")
+	moveDown      = []byte(esc + "1B")
+	saveCursor    = []byte("\0337")
+	restoreCursor = []byte("\0338")
+	re            = regexp.MustCompile(ansi)
 )
 
 // ScreenBuf is a convenient way to write to terminal screens. It creates,
@@ -34,11 +53,103 @@ type ScreenBuf struct {
 	height     int
 	prevBufLen int
 	isSelect   bool
+
+	// width caches the terminal width for the current render pass so it is
+	// only looked up once between a Reset and a Flush. Zero means unknown.
+	width      int
+	fixedWidth bool
+
+	// WidthFunc returns the current width of the terminal. It defaults to
+	// querying the terminal and can be replaced to get deterministic output,
+	// for example in tests.
+	WidthFunc func() (int, error)
+
+	// HeightFunc returns the current height of the terminal. It defaults to
+	// querying the terminal and can be replaced like WidthFunc.
+	HeightFunc func() (int, error)
+
+	// DefaultWidth is the width used when WidthFunc fails or reports a width of
+	// zero, which is common in CI containers and IDE consoles. Defaults to 80.
+	DefaultWidth int
+
+	// DisableDiff forces every line to be cleared and rewritten on each write.
+	// By default, lines identical to the ones displayed by the previous Flush
+	// are skipped to reduce flickering.
+	DisableDiff bool
+
+	// TabWidth is the number of columns between two tab stops used to compute
+	// the width of lines containing tabs. A tab advances to the next tab stop,
+	// so a tab at column 6 with a width of 8 advances to column 8. Defaults to 8.
+	TabWidth int
+
+	// CursorColumns is the number of columns at the end of each line reserved
+	// for the input cursor of a prompt. They are not counted when computing how
+	// many terminal lines a line wraps over, which keeps the cursor on the same
+	// line as the input it follows. Defaults to 2 for prompts and 0 for selects.
+	CursorColumns int
+
+	// ExpandTabs replaces tabs with spaces in the written lines instead of
+	// leaving their expansion to the terminal.
+	ExpandTabs bool
+
+	// ReflowOnResize looks up the terminal width again on each Flush. If the
+	// width changed since the previous frame, all previous lines are cleared and
+	// the frame is fully redrawn to avoid artifacts from the old line wrapping.
+	ReflowOnResize bool
+
+	// Plain writes the lines without any ANSI escape code, for outputs that are
+	// not terminals like pipes and files. The escape codes of the written lines
+	// are removed and each flushed frame is appended below the previous one
+	// instead of replacing it. Frames identical to the previous one are skipped.
+	Plain bool
+
+	// frame holds the lines currently displayed on the terminal.
+	frame [][]byte
+
+	// flushedWidth and flushedHeight are the terminal width used to render the
+	// last Flush and the number of lines it displayed.
+	flushedWidth  int
+	flushedHeight int
+
+	// cleared is set when all previous lines were cleared since the last Flush.
+	cleared bool
+
+	// plainFrame holds the lines of the last frame flushed in Plain mode.
+	plainFrame [][]byte
 }
 
 // New creates and initializes a new ScreenBuf.
 func New(w io.Writer, isSelect bool) *ScreenBuf {
-	return &ScreenBuf{buf: &bytes.Buffer{}, w: w, isSelect: isSelect}
+	return NewWithWidth(w, isSelect, terminalWidth)
+}
+
+// NewWithWidth creates and initializes a new ScreenBuf using widthFn to look up
+// the terminal width.
+func NewWithWidth(w io.Writer, isSelect bool, widthFn func() (int, error)) *ScreenBuf {
+	cursorColumns := DefaultCursorColumns
+	if isSelect {
+		cursorColumns = 0
+	}
+
+	return &ScreenBuf{
+		buf:           &bytes.Buffer{},
+		w:             w,
+		isSelect:      isSelect,
+		WidthFunc:     widthFn,
+		DefaultWidth:  DefaultWidth,
+		TabWidth:      DefaultTabWidth,
+		CursorColumns: cursorColumns,
+	}
+}
+
+func terminalWidth() (int, error) {
+	x, err := terminal.Width()
+	return int(x), err
+}
+
+func terminalHeight() (int, error) {
+	y, err := terminal.Height()
+	return int(y), err
 }
 
 // Reset truncates the underlining buffer and marks all its previous lines to be
@@ -46,11 +157,76 @@ func New(w io.Writer, isSelect bool) *ScreenBuf {
 func (s *ScreenBuf) Reset() {
 	s.buf.Reset()
 	s.reset = true
+	s.invalidateWidth()
+}
+
+// SetWidth forces the terminal width used to compute line wrapping. Once set,
+// the terminal is no longer queried for its width. A value lower than 1 removes
+// the override.
+func (s *ScreenBuf) SetWidth(w int) {
+	if w < 1 {
+		s.fixedWidth = false
+		s.width = 0
+		return
+	}
+	s.fixedWidth = true
+	s.width = w
+}
+
+// Width returns the terminal width used to compute line wrapping, or
+// DefaultWidth if it cannot be detected.
+func (s *ScreenBuf) Width() int {
+	return s.termWidth()
+}
+
+// TerminalHeight returns the number of lines of the terminal, or DefaultHeight
+// if it cannot be detected. Unlike the width, it is looked up on each call.
+func (s *ScreenBuf) TerminalHeight() int {
+	heightFn := s.HeightFunc
+	if heightFn == nil {
+		heightFn = terminalHeight
+	}
+
+	y, err := heightFn()
+	if err != nil || y < 1 {
+		return DefaultHeight
+	}
+	return y
+}
+
+// termWidth returns the cached terminal width, querying the terminal only if
+// it is not known yet for the current render pass. If the width cannot be
+// detected, DefaultWidth is used instead.
+func (s *ScreenBuf) termWidth() int {
+	if s.width > 0 {
+		return s.width
+	}
+
+	widthFn := s.WidthFunc
+	if widthFn == nil {
+		widthFn = terminalWidth
+	}
+
+	x, err := widthFn()
+	if err != nil || x < 1 {
+		x = s.DefaultWidth
+		if x < 1 {
+			x = DefaultWidth
+		}
+	}
+	s.width = x
+	return s.width
+}
+
+func (s *ScreenBuf) invalidateWidth() {
+	if !s.fixedWidth {
+		s.width = 0
+	}
 }
 
 // Clear clears all previous lines and the output starts from the top.
 func (s *ScreenBuf) Clear() error {
-	for i := 0; i < s.height; i++ {
+	for i := 0; i < s.height && !s.Plain; i++ {
 		_, err := s.buf.Write(moveUp)
 		if err != nil {
 			return err
@@ -63,6 +239,32 @@ func (s *ScreenBuf) Clear() error {
 	s.cursor = 0
 	s.height = 0
 	s.reset = false
+	s.frame = s.frame[:0]
+	s.cleared = true
+	return nil
+}
+
+// ClearScreen clears the whole terminal right away and moves the cursor to its
+// top left corner. The lines previously displayed are forgotten, so the next
+// frame is drawn from scratch, as done on <Ctrl+L> when other writes to the
+// terminal corrupted the display. Nothing is written in Plain mode.
+func (s *ScreenBuf) ClearScreen() error {
+	if s.Plain {
+		return nil
+	}
+
+	s.buf.Reset()
+	if _, err := s.w.Write(clearScreen); err != nil {
+		return err
+	}
+
+	s.cursor = 0
+	s.height = 0
+	s.prevBufLen = 0
+	s.reset = false
+	s.frame = s.frame[:0]
+	s.flushedHeight = 0
+	s.cleared = true
 	return nil
 }
 
@@ -81,22 +283,37 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 		}
 	}
 
-	x, err := terminal.Width()
-	if err != nil {
-		return 0, err
+	if s.ExpandTabs {
+		b = []byte(s.expandTabs(string(b)))
+	}
+
+	if s.Plain {
+		b = StripANSI(b)
+		s.setLine(s.cursor, b)
+		s.cursor++
+		s.height = s.cursor
+		return s.buf.Write(append(b, '\n'))
 	}
-	if x > 0 && !s.isSelect {
-		stripped := re.ReplaceAllString(string(b), "")
-		strippedBufLen := utf8.RuneCountInString(stripped) - 2
-		numClearLines := strippedBufLen / int(x)
+
+	x := s.termWidth()
+	if s.unchanged(b, x) {
+		s.prevBufLen = len(b)
+		s.cursor++
+		return s.buf.Write(moveDown)
+	}
+
+	if !s.isSelect {
+		width := s.lineWidth(b)
+		strippedBufLen := width - s.CursorColumns
+		numClearLines := strippedBufLen / x
 
 		for i := 0; i < numClearLines; i++ {
 			s.buf.Write(moveUp)
 			s.buf.Write(clearLine)
 		}
 
-		cond1 := (strippedBufLen+1)%int(x) == 0
-		cond2 := (strippedBufLen+2)%int(x) == 0
+		cond1 := (width-1)%x == 0
+		cond2 := width%x == 0
 		if s.prevBufLen > len(b) && (cond1 || cond2) {
 			// if client is deleting characters
 			s.buf.Write(moveUp)
@@ -116,6 +333,7 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 		if err != nil {
 			return n, err
 		}
+		s.setLine(s.cursor, b)
 		s.height++
 		s.cursor++
 		return n, nil
@@ -132,6 +350,7 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 		if err != nil {
 			return n, err
 		}
+		s.setLine(s.cursor, b)
 		s.cursor++
 		return n, nil
 	default:
@@ -139,14 +358,162 @@ func (s *ScreenBuf) Write(b []byte) (int, error) {
 	}
 }
 
+// unchanged reports whether b is already displayed at the current line, in
+// which case it does not need to be written again. Lines wrapping over the
+// terminal width are always rewritten.
+func (s *ScreenBuf) unchanged(b []byte, width int) bool {
+	if s.DisableDiff || s.cursor >= s.height || s.cursor >= len(s.frame) {
+		return false
+	}
+	return bytes.Equal(s.frame[s.cursor], b) && s.lineWidth(b) < width
+}
+
+// lineWidth returns the number of columns used to display b, expanding tabs to
+// the next tab stop.
+func (s *ScreenBuf) lineWidth(b []byte) int {
+	str := string(b)
+	if strings.ContainsRune(str, '\t') {
+		str = s.expandTabs(str)
+	}
+	return StringWidth(str)
+}
+
+// expandTabs replaces each tab in str with the spaces needed to reach the next
+// tab stop. ANSI escape codes are kept as is and do not count as columns.
+func (s *ScreenBuf) expandTabs(str string) string {
+	tabWidth := s.TabWidth
+	if tabWidth < 1 {
+		tabWidth = DefaultTabWidth
+	}
+
+	var out strings.Builder
+	col := 0
+	codes := re.FindAllStringIndex(str, -1)
+
+	for i := 0; i < len(str); {
+		if len(codes) > 0 && codes[0][0] == i {
+			out.WriteString(str[codes[0][0]:codes[0][1]])
+			i = codes[0][1]
+			codes = codes[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(str[i:])
+		i += size
+
+		if r == '\t' {
+			spaces := tabWidth - col%tabWidth
+			out.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+			continue
+		}
+
+		out.WriteRune(r)
+		col += RuneWidth(r)
+	}
+
+	return out.String()
+}
+
+func (s *ScreenBuf) setLine(i int, b []byte) {
+	for len(s.frame) <= i {
+		s.frame = append(s.frame, nil)
+	}
+	s.frame[i] = append(s.frame[i][:0], b...)
+}
+
+// WriteLineAt replaces the line at the given index of the frame displayed by the
+// last Flush, writing it to the terminal right away without drawing the other
+// lines again, as done to animate a single line like a spinner inside a form.
+// The terminal cursor is saved and restored around the write, and the next
+// frame is diffed against the new line. Lines with \r or \n will cause an
+// error, as will an index outside of the frame, lines written since the last
+// Flush, or a frame with wrapping lines, which do not match a single line of
+// the terminal. Nothing is written in Plain mode, where lines are appended.
+func (s *ScreenBuf) WriteLineAt(index int, b []byte) error {
+	if bytes.ContainsAny(b, "\r\n") {
+		return fmt.Errorf("%q should not contain either \\r or \\n", b)
+	}
+	if s.Plain {
+		return nil
+	}
+	if s.cursor > 0 || s.cleared {
+		return fmt.Errorf("line %d cannot be written before the pending lines are flushed", index)
+	}
+	if index < 0 || index >= s.height || s.height != s.flushedHeight {
+		return fmt.Errorf("line %d is outside of the %d lines displayed", index, s.height)
+	}
+
+	if s.ExpandTabs {
+		b = []byte(s.expandTabs(string(b)))
+	}
+
+	x := s.termWidth()
+	defer s.invalidateWidth()
+	if s.lineWidth(b) >= x {
+		return fmt.Errorf("line %d wraps over the terminal width of %d", index, x)
+	}
+	for i, line := range s.frame[:s.height] {
+		if s.lineWidth(line) >= x {
+			return fmt.Errorf("line %d of the frame wraps over the terminal width of %d", i, x)
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(saveCursor)
+	for i := index; i < s.height; i++ {
+		out.Write(moveUp)
+	}
+	out.Write(clearLine)
+	out.Write(b)
+	out.Write(restoreCursor)
+	if _, err := out.WriteTo(s.w); err != nil {
+		return err
+	}
+
+	s.setLine(index, b)
+	return nil
+}
+
+// WriteLines writes b to the underlining buffer, one line for each segment
+// separated by \n. Each line is written with ScreenBuf.Write() and the total
+// number of bytes written is returned. Lines with \r will cause an error.
+func (s *ScreenBuf) WriteLines(b []byte) (int, error) {
+	if bytes.ContainsRune(b, '\r') {
+		return 0, fmt.Errorf("%q should not contain \\r", b)
+	}
+
+	total := 0
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		n, err := s.Write(line)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
 // Flush writes any buffered data to the underlying io.Writer, ensuring that any pending data is displayed.
 func (s *ScreenBuf) Flush() error {
+	if s.Plain {
+		return s.flushPlain()
+	}
+
+	if s.ReflowOnResize {
+		if err := s.reflow(); err != nil {
+			return err
+		}
+	}
+
 	for i := s.cursor; i < s.height; i++ {
 		if i < s.height {
 			_, err := s.buf.Write(clearLine)
 			if err != nil {
 				return err
 			}
+			s.setLine(i, nil)
 		}
 		_, err := s.buf.Write(moveDown)
 		if err != nil {
@@ -169,10 +536,138 @@ func (s *ScreenBuf) Flush() error {
 	}
 
 	s.cursor = 0
+	s.flushedWidth = s.termWidth()
+	s.flushedHeight = s.height
+	s.cleared = false
+	s.invalidateWidth()
+
+	return nil
+}
+
+// flushPlain writes the buffered lines in Plain mode, unless they are the same
+// as the previous frame.
+func (s *ScreenBuf) flushPlain() error {
+	lines := s.frame[:s.cursor]
+	same := len(lines) == len(s.plainFrame)
+	for i := 0; same && i < len(lines); i++ {
+		same = bytes.Equal(lines[i], s.plainFrame[i])
+	}
+
+	if !same {
+		if _, err := s.buf.WriteTo(s.w); err != nil {
+			return err
+		}
+
+		s.plainFrame = s.plainFrame[:0]
+		for _, line := range lines {
+			s.plainFrame = append(s.plainFrame, append([]byte(nil), line...))
+		}
+	}
 
+	s.buf.Reset()
+	s.cursor = 0
+	s.height = 0
+	s.reset = false
+	s.frame = s.frame[:0]
 	return nil
 }
 
+// reflow redraws the current frame from scratch if the terminal width changed
+// since the previous Flush.
+func (s *ScreenBuf) reflow() error {
+	s.invalidateWidth()
+	width := s.termWidth()
+	if s.cleared || s.flushedWidth == 0 || width == s.flushedWidth {
+		return nil
+	}
+
+	lines := make([][]byte, s.cursor)
+	for i := range lines {
+		lines[i] = append([]byte(nil), s.frame[i]...)
+	}
+
+	// the buffered lines are discarded, so the terminal still displays the
+	// previous frame.
+	s.buf.Reset()
+	s.height = s.flushedHeight
+	if err := s.Clear(); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if _, err := s.Write(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlushFinal writes any buffered data to the underlying io.Writer like Flush,
+// but leaves the terminal cursor below the last written line instead of moving
+// it back to the top. It should be used for the last frame, so any following
+// output appears below it. The next Write starts a new block of lines.
+func (s *ScreenBuf) FlushFinal() error {
+	if s.Plain {
+		return s.flushPlain()
+	}
+
+	for i := s.cursor; i < s.height; i++ {
+		_, err := s.buf.Write(clearLine)
+		if err != nil {
+			return err
+		}
+		_, err = s.buf.Write(moveDown)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := s.cursor; i < s.height; i++ {
+		_, err := s.buf.Write(moveUp)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := s.buf.WriteTo(s.w)
+	if err != nil {
+		return err
+	}
+
+	s.buf.Reset()
+	s.cursor = 0
+	s.height = 0
+	s.reset = false
+	s.frame = s.frame[:0]
+	s.invalidateWidth()
+
+	return nil
+}
+
+// Cursor returns the line the next Write will be written to, relative to the
+// top of the rendered block.
+func (s *ScreenBuf) Cursor() int {
+	return s.cursor
+}
+
+// Height returns the number of lines currently rendered by the ScreenBuf.
+func (s *ScreenBuf) Height() int {
+	return s.height
+}
+
+// Bytes returns the content currently buffered, ie: everything written since
+// the last Flush, including the ANSI escape codes used to move between lines.
+func (s *ScreenBuf) Bytes() []byte {
+	return s.buf.Bytes()
+}
+
+// StripANSI removes all ANSI escape codes from b, leaving only the visible
+// text.
+func StripANSI(b []byte) []byte {
+	return re.ReplaceAll(b, nil)
+}
+
 // WriteString is a convenient function to write a new line passing a string.
 // Check ScreenBuf.Write() for a detailed explanation of the function behaviour.
 func (s *ScreenBuf) WriteString(str string) (int, error) {
@@ -2,6 +2,8 @@ package screenbuf
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -12,7 +14,9 @@ func TestScreen(t *testing.T) {
 	moveDown = []byte("\\d")
 
 	var buf bytes.Buffer
-	s := New(&buf)
+	s := New(&buf, false)
+	s.SetWidth(80)
+	s.DisableDiff = true
 
 	tcs := []struct {
 		scenario string
@@ -111,8 +115,8 @@ func TestScreen(t *testing.T) {
 				}
 			}
 
-			if tc.cursor != s.cursor {
-				t.Errorf("expected cursor %d, got %d", tc.cursor, s.cursor)
+			if tc.cursor != s.Cursor() {
+				t.Errorf("expected cursor %d, got %d", tc.cursor, s.Cursor())
 			}
 
 			err := s.Flush()
@@ -126,9 +130,552 @@ func TestScreen(t *testing.T) {
 				t.Errorf("expected %q, got %q", tc.expect, got)
 			}
 
-			if tc.height != s.height {
-				t.Errorf("expected height %d, got %d", tc.height, s.height)
+			if tc.height != s.Height() {
+				t.Errorf("expected height %d, got %d", tc.height, s.Height())
 			}
 		})
 	}
 }
+
+func TestScreenDiff(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	var buf bytes.Buffer
+	s := New(&buf, true)
+	s.SetWidth(80)
+
+	tcs := []struct {
+		scenario string
+		lines    []string
+		expect   string
+	}{
+		{
+			scenario: "initial write",
+			lines:    []string{"line one", "line two", "line three"},
+			expect:   "\\cline one\n\\cline two\n\\cline three\n",
+		},
+		{
+			scenario: "only changed lines are rewritten",
+			lines:    []string{"line one", "line 2", "line three"},
+			expect:   "\\u\\u\\u\\d\\cline 2\\d\\d",
+		},
+		{
+			scenario: "lines cleared by a shorter frame are rewritten",
+			lines:    []string{"line one"},
+			expect:   "\\u\\u\\u\\d\\c\\d\\c\\d",
+		},
+		{
+			scenario: "write after clear lines",
+			lines:    []string{"line one", "line 2"},
+			expect:   "\\u\\u\\u\\d\\cline 2\\d\\c\\d",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			buf.Reset()
+
+			for _, line := range tc.lines {
+				_, err := s.WriteString(line)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			err := s.Flush()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := buf.String()
+			if tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestSetWidth(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, false)
+
+	s.SetWidth(20)
+	s.Reset()
+	if s.width != 20 {
+		t.Errorf("expected forced width to survive reset, got %d", s.width)
+	}
+
+	s.SetWidth(0)
+	if s.fixedWidth || s.width != 0 {
+		t.Errorf("expected width override to be removed, got %d", s.width)
+	}
+}
+
+func TestScreenWrap(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	var buf bytes.Buffer
+	s := NewWithWidth(&buf, false, func() (int, error) {
+		return 20, nil
+	})
+
+	tcs := []struct {
+		scenario string
+		line     string
+		expect   string
+	}{
+		{
+			scenario: "line wrapping over three rows",
+			line:     strings.Repeat("a", 45),
+			expect:   "\\u\\c\\u\\c\\c" + strings.Repeat("a", 45) + "\n",
+		},
+		{
+			scenario: "deleting back to a row boundary",
+			line:     strings.Repeat("a", 40),
+			expect:   "\\u\\u\\c\\u\\c\\c" + strings.Repeat("a", 40) + "\\d",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			buf.Reset()
+
+			_, err := s.WriteString(tc.line)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			err = s.Flush()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := buf.String()
+			if tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	clearLine = []byte(esc + "2K\r")
+	moveUp = []byte(esc + "1A")
+	moveDown = []byte(esc + "1B")
+
+	var buf bytes.Buffer
+	s := New(&buf, true)
+	s.SetWidth(80)
+
+	s.WriteString("\033[1mLine One\033[0m")
+	s.WriteString("Line Two")
+
+	got := string(StripANSI(s.Bytes()))
+	expect := "\rLine One\n\rLine Two\n"
+	if expect != got {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestDefaultWidth(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWithWidth(&buf, false, func() (int, error) {
+		return 0, errors.New("not a terminal")
+	})
+	s.DefaultWidth = 40
+
+	_, err := s.WriteString("Line One")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if s.width != 40 {
+		t.Errorf("expected width to fall back to 40, got %d", s.width)
+	}
+}
+
+func TestScreenWrapWide(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	var buf bytes.Buffer
+	s := NewWithWidth(&buf, false, func() (int, error) {
+		return 20, nil
+	})
+
+	// 12 wide runes use 24 columns, wrapping once on a 20 columns terminal.
+	line := strings.Repeat("你", 12)
+	_, err := s.WriteString(line)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = s.Flush()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expect := "\\u\\c\\c" + line + "\n"
+	got := buf.String()
+	if expect != got {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestWriteLines(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	t.Run("splits on new lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := New(&buf, true)
+		s.SetWidth(80)
+
+		_, err := s.WriteLines([]byte("Line One\nLine Two"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if s.height != 2 {
+			t.Errorf("expected height 2, got %d", s.height)
+		}
+
+		err = s.Flush()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expect := "\\cLine One\n\\cLine Two\n"
+		got := buf.String()
+		if expect != got {
+			t.Errorf("expected %q, got %q", expect, got)
+		}
+	})
+
+	t.Run("rejects carriage returns", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := New(&buf, true)
+
+		_, err := s.WriteLines([]byte("Line One\r\nLine Two"))
+		if err == nil {
+			t.Errorf("expected error, got none")
+		}
+	})
+}
+
+func TestExpandTabs(t *testing.T) {
+	s := New(&bytes.Buffer{}, false)
+
+	tcs := []struct {
+		scenario string
+		input    string
+		expect   string
+	}{
+		{scenario: "tab at column 6", input: "abcdef\tg", expect: "abcdef  g"},
+		{scenario: "tab at a tab stop", input: "abcdefgh\ti", expect: "abcdefgh        i"},
+		{scenario: "styled text", input: "\033[1mab\033[0m\tc", expect: "\033[1mab\033[0m      c"},
+		{scenario: "wide runes", input: "你好\tc", expect: "你好    c"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := s.expandTabs(tc.input)
+			if tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+
+	if w := s.lineWidth([]byte("abcdef\tg")); w != 9 {
+		t.Errorf("expected width 9, got %d", w)
+	}
+}
+
+func TestScreenWrapBoundaries(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	tcs := []struct {
+		scenario      string
+		columns       int
+		cursorColumns int
+		cleared       int
+	}{
+		{scenario: "exactly the terminal width", columns: 20, cursorColumns: 2, cleared: 0},
+		{scenario: "one column over the terminal width", columns: 21, cursorColumns: 2, cleared: 0},
+		{scenario: "cursor columns over the terminal width", columns: 22, cursorColumns: 2, cleared: 1},
+		{scenario: "twice the terminal width", columns: 40, cursorColumns: 2, cleared: 1},
+		{scenario: "twice the terminal width with cursor columns", columns: 42, cursorColumns: 2, cleared: 2},
+		{scenario: "exactly the terminal width without cursor columns", columns: 20, cursorColumns: 0, cleared: 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := NewWithWidth(&buf, false, func() (int, error) {
+				return 20, nil
+			})
+			s.CursorColumns = tc.cursorColumns
+
+			line := strings.Repeat("a", tc.columns)
+			_, err := s.WriteString(line)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			expect := strings.Repeat("\\u\\c", tc.cleared) + "\\c" + line + "\n"
+			got := string(s.Bytes())
+			if expect != got {
+				t.Errorf("expected %q, got %q", expect, got)
+			}
+		})
+	}
+}
+
+func TestFlushFinal(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	var buf bytes.Buffer
+	s := New(&buf, true)
+	s.SetWidth(80)
+
+	s.WriteString("line one")
+	s.WriteString("line two")
+	s.WriteString("line three")
+	s.Flush()
+	buf.Reset()
+
+	s.WriteString("selected")
+	err := s.FlushFinal()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expect := "\\u\\u\\u\\cselected\\d\\c\\d\\c\\d\\u\\u"
+	if got := buf.String(); expect != got {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+
+	buf.Reset()
+	s.WriteString("next")
+	s.Flush()
+
+	expect = "\\cnext\n"
+	if got := buf.String(); expect != got {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestReflowOnResize(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+
+	width := 20
+	var buf bytes.Buffer
+	s := NewWithWidth(&buf, true, func() (int, error) {
+		return width, nil
+	})
+	s.ReflowOnResize = true
+
+	tcs := []struct {
+		scenario string
+		width    int
+		lines    []string
+		expect   string
+	}{
+		{
+			scenario: "initial write",
+			width:    20,
+			lines:    []string{"line one", "line two"},
+			expect:   "\\cline one\n\\cline two\n",
+		},
+		{
+			scenario: "write with the same width",
+			width:    20,
+			lines:    []string{"line one", "line 2"},
+			expect:   "\\u\\u\\d\\cline 2\\d",
+		},
+		{
+			scenario: "write after a resize",
+			width:    30,
+			lines:    []string{"line one", "line 2", "line three"},
+			expect:   "\\u\\c\\u\\c\\cline one\n\\cline 2\n\\cline three\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			buf.Reset()
+			width = tc.width
+
+			for _, line := range tc.lines {
+				_, err := s.WriteString(line)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			err := s.Flush()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got := buf.String(); tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestPlain(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, false)
+	s.SetWidth(80)
+	s.Plain = true
+
+	s.Write([]byte("\x1b[1mhello\x1b[0m"))
+	s.Write([]byte("world"))
+	s.Flush()
+
+	s.Reset()
+	s.Write([]byte("hello"))
+	s.Write([]byte("world"))
+	s.Flush()
+
+	s.Reset()
+	s.Write([]byte("done"))
+	s.FlushFinal()
+
+	expected := "hello\nworld\ndone\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestTerminalHeight(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, true)
+
+	s.HeightFunc = func() (int, error) { return 40, nil }
+	if h := s.TerminalHeight(); h != 40 {
+		t.Errorf("expected a height of 40, got %d", h)
+	}
+
+	s.HeightFunc = func() (int, error) { return 0, errors.New("not a terminal") }
+	if h := s.TerminalHeight(); h != DefaultHeight {
+		t.Errorf("expected height to fall back to %d, got %d", DefaultHeight, h)
+	}
+}
+
+func TestClearScreen(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+	clearScreen = []byte("\\s")
+
+	var buf bytes.Buffer
+	s := New(&buf, true)
+	s.SetWidth(80)
+
+	s.WriteString("line one")
+	s.WriteString("line two")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	buf.Reset()
+
+	if err := s.ClearScreen(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	s.WriteString("line one")
+	s.WriteString("line two")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expect := "\\s\\cline one\n\\cline two\n"
+	if got := buf.String(); got != expect {
+		t.Errorf("expected the whole frame drawn again %q, got %q", expect, got)
+	}
+	if s.Height() != 2 {
+		t.Errorf("expected height 2, got %d", s.Height())
+	}
+}
+
+func TestWriteLineAt(t *testing.T) {
+	clearLine = []byte("\\c")
+	moveUp = []byte("\\u")
+	moveDown = []byte("\\d")
+	saveCursor = []byte("\\<")
+	restoreCursor = []byte("\\>")
+
+	var buf bytes.Buffer
+	s := New(&buf, true)
+	s.SetWidth(20)
+
+	if err := s.WriteLineAt(0, []byte("line one")); err == nil {
+		t.Errorf("expected an error before the first flush")
+	}
+
+	s.WriteString("line one")
+	s.WriteString("line two")
+	s.WriteString("line three")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	buf.Reset()
+
+	if err := s.WriteLineAt(1, []byte("line 2")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expect := "\\<\\u\\u\\cline 2\\>"
+	if got := buf.String(); got != expect {
+		t.Errorf("expected only the second line written %q, got %q", expect, got)
+	}
+	buf.Reset()
+
+	s.WriteString("line one")
+	s.WriteString("line 2")
+	s.WriteString("line three")
+	if err := s.Flush(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expect = "\\u\\u\\u\\d\\d\\d"
+	if got := buf.String(); got != expect {
+		t.Errorf("expected the updated line to be diffed %q, got %q", expect, got)
+	}
+
+	tcs := []struct {
+		name  string
+		index int
+		line  string
+	}{
+		{"negative index", -1, "line"},
+		{"index below the frame", 3, "line"},
+		{"new line", 0, "line\none"},
+		{"wrapping line", 0, "a line wider than the terminal"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := s.WriteLineAt(tc.index, []byte(tc.line)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+
+	s.WriteString("pending")
+	if err := s.WriteLineAt(0, []byte("line one")); err == nil {
+		t.Errorf("expected an error with pending lines")
+	}
+}
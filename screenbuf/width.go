@@ -0,0 +1,133 @@
+package screenbuf
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// zeroWidth are the runes that do not advance the cursor, like combining marks
+// and zero width joiners used inside emoji sequences.
+var zeroWidth = []*unicode.RangeTable{
+	unicode.Mn,
+	unicode.Me,
+	unicode.Cc,
+	unicode.Cf,
+	unicode.Variation_Selector,
+}
+
+// wideRanges are the ranges of runes displayed over two columns by most
+// terminals, mostly the East Asian wide and fullwidth characters and emoji.
+var wideRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2329, Hi: 0x232a, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274c, Stride: 1},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1},
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1},
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1},
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1},
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1},
+		{Lo: 0xa960, Hi: 0xa97f, Stride: 1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe10, Hi: 0xfe19, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe6f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x16fe0, Hi: 0x16fe4, Stride: 1},
+		{Lo: 0x17000, Hi: 0x18cff, Stride: 1},
+		{Lo: 0x1b000, Hi: 0x1b2ff, Stride: 1},
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f265, Stride: 1},
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x1fa70, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1},
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1},
+	},
+}
+
+// RuneWidth returns the number of columns used by the terminal to display r.
+func RuneWidth(r rune) int {
+	switch {
+	case unicode.IsOneOf(zeroWidth, r):
+		return 0
+	case unicode.Is(wideRanges, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth returns the number of columns used by the terminal to display
+// str. ANSI escape codes are ignored since they are not displayed.
+func StringWidth(str string) int {
+	width := 0
+	for _, r := range re.ReplaceAllString(str, "") {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// Truncate cuts str to width columns, ending it with tail when it is cut, as
+// with an ellipsis. The width of tail is included in width, and tail is left
+// out if it is wider. The ANSI escape codes are kept, including those after
+// the cut, so a style reset at the end of str still applies.
+func Truncate(str string, width int, tail string) string {
+	if StringWidth(str) <= width {
+		return str
+	}
+
+	budget := width - StringWidth(tail)
+	if budget < 0 {
+		budget, tail = width, ""
+	}
+
+	var b strings.Builder
+	curWidth := 0
+	cut := false
+
+	escapes := re.FindAllStringIndex(str, -1)
+	for i := 0; i < len(str); {
+		if len(escapes) > 0 && escapes[0][0] == i {
+			b.WriteString(str[i:escapes[0][1]])
+			i = escapes[0][1]
+			escapes = escapes[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if w := RuneWidth(r); !cut && curWidth+w <= budget {
+			b.WriteString(str[i : i+size])
+			curWidth += w
+		} else if !cut {
+			b.WriteString(tail)
+			cut = true
+		}
+		i += size
+	}
+
+	return b.String()
+}
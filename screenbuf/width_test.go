@@ -0,0 +1,51 @@
+package screenbuf
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		input    string
+		expect   int
+	}{
+		{scenario: "ascii", input: "hello", expect: 5},
+		{scenario: "styled", input: "\033[1mhello\033[0m", expect: 5},
+		{scenario: "cjk", input: "你好", expect: 4},
+		{scenario: "emoji", input: "🔥 hot", expect: 6},
+		{scenario: "combining mark", input: "é", expect: 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := StringWidth(tc.input)
+			if tc.expect != got {
+				t.Errorf("expected width %d, got %d", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		input    string
+		width    int
+		expect   string
+	}{
+		{scenario: "fitting", input: "hello", width: 5, expect: "hello"},
+		{scenario: "cut", input: "hello world", width: 6, expect: "hello…"},
+		{scenario: "wide runes", input: "你好世界", width: 6, expect: "你好…"},
+		{scenario: "wide rune at the cut", input: "a你好", width: 3, expect: "a…"},
+		{scenario: "styled", input: "\033[1mhello\033[0m", width: 3, expect: "\033[1mhe…\033[0m"},
+		{scenario: "narrower than the tail", input: "hello", width: 0, expect: ""},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := Truncate(tc.input, tc.width, "…")
+			if tc.expect != got {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
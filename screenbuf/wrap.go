@@ -0,0 +1,99 @@
+package screenbuf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap splits str into lines no wider than width columns, breaking them at
+// spaces where possible. The line breaks already in str are kept. Words wider
+// than width are split between runes. The width of the runes is computed like
+// StringWidth and ANSI escape codes are never split, so the styles of the text
+// are kept. A width lower than 1 only splits str at its line breaks.
+func Wrap(str string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(str, "\n") {
+		if width < 1 || StringWidth(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	return lines
+}
+
+func wrapLine(line string, width int) []string {
+	var lines []string
+	var cur strings.Builder
+	curWidth := 0
+	started := false
+
+	for _, word := range strings.Split(line, " ") {
+		wordWidth := StringWidth(word)
+
+		switch {
+		case !started:
+		case curWidth+1+wordWidth <= width:
+			cur.WriteByte(' ')
+			curWidth++
+		default:
+			lines = append(lines, cur.String())
+			cur.Reset()
+			curWidth = 0
+		}
+		started = true
+
+		if curWidth+wordWidth <= width {
+			cur.WriteString(word)
+			curWidth += wordWidth
+			continue
+		}
+
+		// the word does not fit on a line of its own, it is split between runes.
+		parts := splitWord(word, width)
+		for _, part := range parts[:len(parts)-1] {
+			lines = append(lines, part.text)
+		}
+		cur.WriteString(parts[len(parts)-1].text)
+		curWidth = parts[len(parts)-1].width
+	}
+
+	return append(lines, cur.String())
+}
+
+// wordPart is a part of a word split by splitWord, with its display width.
+type wordPart struct {
+	text  string
+	width int
+}
+
+// splitWord splits word into parts no wider than width columns. The ANSI
+// escape codes are kept whole inside the parts.
+func splitWord(word string, width int) []wordPart {
+	var parts []wordPart
+	var cur strings.Builder
+	curWidth := 0
+
+	escapes := re.FindAllStringIndex(word, -1)
+	for i := 0; i < len(word); {
+		if len(escapes) > 0 && escapes[0][0] == i {
+			cur.WriteString(word[i:escapes[0][1]])
+			i = escapes[0][1]
+			escapes = escapes[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(word[i:])
+		w := RuneWidth(r)
+		if curWidth > 0 && curWidth+w > width {
+			parts = append(parts, wordPart{text: cur.String(), width: curWidth})
+			cur.Reset()
+			curWidth = 0
+		}
+		cur.WriteString(word[i : i+size])
+		curWidth += w
+		i += size
+	}
+
+	return append(parts, wordPart{text: cur.String(), width: curWidth})
+}
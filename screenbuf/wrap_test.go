@@ -0,0 +1,34 @@
+package screenbuf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tcs := []struct {
+		scenario string
+		str      string
+		width    int
+		expected []string
+	}{
+		{"short line", "hello world", 20, []string{"hello world"}},
+		{"breaks at spaces", "the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"keeps line breaks", "one\ntwo three", 5, []string{"one", "two", "three"}},
+		{"splits long words", "abcdefghij xy", 4, []string{"abcd", "efgh", "ij", "xy"}},
+		{"counts wide runes", "日本語 日本", 6, []string{"日本語", "日本"}},
+		{"splits wide runes", "日本語日本", 5, []string{"日本", "語日", "本"}},
+		{"ignores escape codes", "\x1b[31mred\x1b[0m blue", 8, []string{"\x1b[31mred\x1b[0m blue"}},
+		{"never splits escape codes", "\x1b[31mabcdef\x1b[0m", 3, []string{"\x1b[31mabc", "def\x1b[0m"}},
+		{"no width", "a b\nc", 0, []string{"a b", "c"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := Wrap(tc.str, tc.width)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
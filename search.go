@@ -0,0 +1,76 @@
+package promptui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/logrhythm/promptui/list"
+)
+
+// SubstringSearcher returns a Searcher matching the items whose label contains the searched term. The label of
+// the item at each index is given by get. The comparison ignores the case and the spaces around the term.
+func SubstringSearcher(get func(index int) string) list.Searcher {
+	return func(input string, index int) bool {
+		input = strings.ToLower(strings.TrimSpace(input))
+		return strings.Contains(strings.ToLower(get(index)), input)
+	}
+}
+
+// FuzzyScorer returns a Scorer matching the items whose label contains all the runes of the searched term in
+// the same order, ranking the closest matches first. The label of the item at each index is given by get. See
+// list.FuzzyScore for more info.
+func FuzzyScorer(get func(index int) string) list.Scorer {
+	return func(input string, index int) (int, bool) {
+		return list.FuzzyScore(strings.TrimSpace(input), get(index))
+	}
+}
+
+// SubstringMatcher returns a Matcher giving the positions of the searched term inside the label of the items,
+// matching like the SubstringSearcher. The label of the item at each index is given by get.
+func SubstringMatcher(get func(index int) string) list.Matcher {
+	return func(input string, index int) []int {
+		term := lowerRunes(strings.TrimSpace(input))
+		label := lowerRunes(get(index))
+		if len(term) == 0 {
+			return nil
+		}
+
+		for i := 0; i+len(term) <= len(label); i++ {
+			if string(label[i:i+len(term)]) != string(term) {
+				continue
+			}
+
+			positions := make([]int, len(term))
+			for j := range positions {
+				positions[j] = i + j
+			}
+			return positions
+		}
+		return nil
+	}
+}
+
+// lowerRunes returns the runes of str in lower case, one for each rune of str so the positions are kept.
+func lowerRunes(str string) []rune {
+	runes := []rune(str)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// FuzzyMatcher returns a Matcher giving the positions of the runes of the searched term inside the label of the
+// items, matching like the FuzzyScorer. The label of the item at each index is given by get.
+func FuzzyMatcher(get func(index int) string) list.Matcher {
+	return func(input string, index int) []int {
+		_, positions, _ := list.FuzzyMatch(strings.TrimSpace(input), get(index))
+		return positions
+	}
+}
+
+// NewStringSearcher returns a SubstringSearcher for a Select whose Items are the given strings.
+func NewStringSearcher(items []string) list.Searcher {
+	return SubstringSearcher(func(index int) string {
+		return items[index]
+	})
+}
@@ -0,0 +1,76 @@
+package promptui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/logrhythm/promptui/list"
+)
+
+func TestSubstringSearcher(t *testing.T) {
+	items := []string{"Bell Pepper", "Banana Pepper", "Habanero"}
+	searcher := NewStringSearcher(items)
+
+	tcs := []struct {
+		input    string
+		expected []bool
+	}{
+		{input: "pepper", expected: []bool{true, true, false}},
+		{input: "  BAN ", expected: []bool{false, true, true}},
+		{input: "", expected: []bool{true, true, true}},
+		{input: "jalapeño", expected: []bool{false, false, false}},
+	}
+
+	for _, tc := range tcs {
+		for i, exp := range tc.expected {
+			if got := searcher(tc.input, i); got != exp {
+				t.Errorf("Expected %q matching %q to be %v, got %v", tc.input, items[i], exp, got)
+			}
+		}
+	}
+}
+
+func TestFuzzyScorer(t *testing.T) {
+	items := []string{"Bell Pepper", "Habanero"}
+	scorer := FuzzyScorer(func(index int) string {
+		return items[index]
+	})
+
+	if _, matched := scorer(" bpp ", 0); !matched {
+		t.Errorf("Expected %q to match %q", "bpp", items[0])
+	}
+
+	if _, matched := scorer("bpp", 1); matched {
+		t.Errorf("Expected %q not to match %q", "bpp", items[1])
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	items := []string{"Bell Pepper", "Habanero"}
+	get := func(index int) string {
+		return items[index]
+	}
+
+	tcs := []struct {
+		scenario string
+		matcher  list.Matcher
+		input    string
+		index    int
+		expected []int
+	}{
+		{scenario: "substring", matcher: SubstringMatcher(get), input: "PEP", index: 0, expected: []int{5, 6, 7}},
+		{scenario: "substring without match", matcher: SubstringMatcher(get), input: "pep", index: 1},
+		{scenario: "substring without term", matcher: SubstringMatcher(get), input: " ", index: 0},
+		{scenario: "fuzzy", matcher: FuzzyMatcher(get), input: "bpp", index: 0, expected: []int{0, 5, 7}},
+		{scenario: "fuzzy without match", matcher: FuzzyMatcher(get), input: "bpp", index: 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			got := tc.matcher(tc.input, tc.index)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
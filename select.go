@@ -2,15 +2,22 @@ package promptui
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/chzyer/readline"
 	"github.com/juju/ansiterm"
-	"github.com/manifoldco/promptui/list"
-	"github.com/manifoldco/promptui/screenbuf"
+	"github.com/logrhythm/promptui/list"
+	"github.com/logrhythm/promptui/screenbuf"
 )
 
 // SelectedAdd is used internally inside SelectWithAdd when the add option is selected in select mode.
@@ -38,11 +45,27 @@ type Select struct {
 	// For example, `{{ .Name }}` will display the name property of a struct.
 	Items interface{}
 
+	// Project is an optional function returning the label and the details displayed for the item at the given
+	// index, instead of the items themselves. When set, the templates of the items receive the label and the
+	// Details template receives the details, so arbitrary values like maps or nested structs are displayed
+	// without reflecting into them. The label is also searched unless a Searcher or a Scorer is set, and
+	// returned by Run.
+	Project func(index int) (label, details string)
+
 	// Size is the number of items that should appear on the select before scrolling is necessary. Defaults to 5.
 	Size int
 
+	// AutoSize sets the number of visible items to fill the height of the terminal instead of using Size, leaving
+	// room for the help, the label and the details of the active item. It is computed again each time the height
+	// of the terminal changes, and the lines are reflowed when its width changes.
+	AutoSize bool
+
 	// IsVimMode sets whether to use vim mode when using readline in the command prompt. Look at
 	// https://godoc.org/github.com/chzyer/readline#Config for more information on readline.
+	//
+	// In vim mode, enter confirms the term typed after the Search key rather than selecting an item. The full list
+	// is displayed again, with the cursor on the first match, and the n and N keys move the cursor to the next and
+	// previous matches.
 	IsVimMode bool
 
 	// HideHelp sets whether to hide help information.
@@ -51,6 +74,33 @@ type Select struct {
 	// HideSelected sets whether to hide the text displayed after an item is successfully selected.
 	HideSelected bool
 
+	// AutoSelectSingle selects the item of a select with a single item without reading the input, displaying it
+	// with the Selected template unless HideSelected is set. It is ignored with ItemsFunc, whose items are not
+	// known up front, and when the single item is disabled.
+	AutoSelectSingle bool
+
+	// MaxLines is the maximum number of lines the select displays, including the help, the label and the details,
+	// for a select embedded in a larger interface whose content must not be scrolled off. When the lines do not
+	// fit, the details are truncated first, then the help is hidden, then fewer items are visible, down to a
+	// single one, and the label is truncated last. Unlike Size, it counts lines rather than items. The line of
+	// the Footer is always kept. Defaults to 0 for no maximum.
+	MaxLines int
+
+	// WrapWidth is the number of columns the label and the details are word-wrapped to, so long lines do not
+	// wrap unpredictably. Defaults to 0 for the width of the terminal.
+	WrapWidth int
+
+	// HideLabel sets whether to hide the label displayed above the list. Combined with HideSelected, or a
+	// Selected template rendering nothing, a completed select leaves no line behind.
+	HideLabel bool
+
+	// Footer is an optional function returning a status line displayed last, below the list and the details,
+	// for example a hint or the metadata of the active item. It is called with the index of the active item in
+	// Items each time the select is drawn, or with list.NotFound when no item matches the search. Unlike the
+	// details, the line is always displayed while Footer is set, even when it is empty, so the select keeps its
+	// height as the status changes. It is cut to a single line of the terminal, with its own styles.
+	Footer func(index int) string
+
 	// Templates can be used to customize the select output. If nil is passed, the
 	// default templates are used. See the SelectTemplates docs for more info.
 	Templates *SelectTemplates
@@ -63,21 +113,201 @@ type Select struct {
 	//
 	// Search is a function that will receive the searched term and the item's index and should return a boolean
 	// for whether or not the terms are alike. It is unimplemented by default and search will not work unless
-	// it is implemented.
+	// it is implemented. NewStringSearcher and SubstringSearcher build a case-insensitive substring search.
 	Searcher list.Searcher
 
+	// Matcher is an optional function returning the positions of the runes matching the searched term inside the
+	// label of the item at the given index. In the templates of the items, the highlight function styles the
+	// matching runes of the label given to it, for example `{{ .Name | highlight }}`. SubstringMatcher and
+	// FuzzyMatcher return the positions matched by SubstringSearcher and FuzzyScorer.
+	Matcher list.Matcher
+
+	// Scorer is an optional function ranking the items matching the searched term, used instead of the Searcher.
+	// The matching items are displayed with the highest scores first, except when using Groups where they keep
+	// their order. FuzzyScorer builds a fuzzy search like fzf.
+	Scorer list.Scorer
+
+	// WrapNavigation makes the Next key move from the last item to the first one and the Prev key from the first
+	// item to the last one. Defaults to false, stopping at the ends of the list.
+	WrapNavigation bool
+
+	// Bell rings the bell of the terminal when the Next or Prev key is pressed at an end of the list it cannot
+	// move past, which flashes the terminals with a visual bell. Nothing is rung when the output is not a
+	// terminal.
+	Bell bool
+
+	// TypeAhead moves the cursor to the next item starting with the characters typed outside of search mode,
+	// without filtering the list. The typed characters are accumulated until no key is pressed for a second.
+	// When set, the h, j, k and l keys no longer move through the list.
+	TypeAhead bool
+
+	// DefaultItem is an optional item on which the cursor is initially placed, compared to each of the items
+	// with reflect.DeepEqual. If no item is equal to it, the cursor starts on the first item. When set, it takes
+	// precedence over the cursor position given to RunCursorAt.
+	DefaultItem interface{}
+
+	// ItemsFunc is an optional function loading the items by pages instead of using Items, for items coming from
+	// a remote source. It returns at most limit items starting at the given offset, and whether more items are
+	// available after them. The first page is loaded by Run, the next ones in the background as the cursor gets
+	// close to the last loaded item, while the Loading template is displayed below the list. ItemsFunc cannot be
+	// used with Groups.
+	ItemsFunc func(offset, limit int) ([]interface{}, bool, error)
+
+	// Groups are optional sections of items displayed instead of Items, each one after a header line using the
+	// Header template. The header lines cannot be selected and the index returned by Run, as well as the index
+	// given to the Searcher and Disabled functions, is the index of the item among the items of all groups. When
+	// searching, the groups without any matching item are hidden.
+	Groups []SelectGroup
+
+	// Disabled is an optional function reporting whether the item at the given index of Items cannot be
+	// selected, for example an unavailable option. Disabled items are displayed with the Disabled template and
+	// skipped when moving through the list.
+	Disabled func(index int) bool
+
 	// StartInSearchMode sets whether or not the select mode should start in search mode or selection mode.
 	// For search mode to work, the Search property must be implemented.
 	StartInSearchMode bool
 
+	// InitialSearch is the term searched when the select starts, like a previous query, with the cursor on the
+	// first matching item. Combined with StartInSearchMode, the term can be edited right away. Without it, the
+	// matches are displayed below the term until it is cleared.
+	InitialSearch string
+
+	// SearchDebounce delays the search until no key was pressed for the given duration, for the Searchers too
+	// slow to run on each key. The typed term is displayed right away, the matching items once they are found.
+	// Defaults to 0, searching on each key.
+	SearchDebounce time.Duration
+
+	// ShowCursor keeps the cursor of the terminal visible while the select is displayed, for users who want to
+	// see it while typing a search. By default, it is hidden until the select ends, so it does not flicker at the
+	// bottom of the list as it is drawn.
+	ShowCursor bool
+
+	// ForceColors keeps the styles and the cursor movements when the output is not a terminal. By default, the
+	// list is written as plain text appended line by line to pipes and files.
+	ForceColors bool
+
+	// PlainSuccess removes the styles from the lines of the Selected template left once an item is selected,
+	// keeping them while the list is displayed, for outputs that are both displayed and logged.
+	PlainSuccess bool
+
+	// TranscriptWriter is an optional writer to which a plain "Label: answer" line is appended once an item is
+	// selected, for an audit log of what was asked and answered. The answer is the label of the item, or the
+	// labels of the selected items of a MultiSelect separated by commas. Nothing is written when the select is
+	// aborted or its input ends.
+	TranscriptWriter io.Writer
+
+	// EnableMouse turns on the mouse reporting of the terminal while the select runs. The wheel moves the cursor
+	// like the Prev and Next keys, a click on an item moves the cursor to it and a double click selects it.
+	EnableMouse bool
+
+	// Icons is the icon set used by the default templates and the icon functions of the templates. Defaults to the
+	// package icons, such as IconInitial.
+	Icons *IconSet
+
+	// OnSelect is an optional function called with the index of the selected item once it is selected, before Run
+	// returns. For a MultiSelect, it is called for each selected item, in the order of the items. Reordering the
+	// Items from it, like list.List.MoveToFront does, keeps the most recently used items first on the next Run.
+	OnSelect func(index int)
+
+	// ExtraKeys are optional actions bound to keys not used by the select, called with the index of the item
+	// under the cursor, or list.NotFound if there is none. They are not called in search mode, and bound letters like
+	// j or k take precedence over the vim-like movements. If an action returns an error, the select ends and
+	// returns it along with the index and the item under the cursor, so a sentinel error can tell which action
+	// was chosen, for example to edit or delete the item.
+	ExtraKeys map[rune]func(index int) error
+
+	// Shortcuts numbers the first nine visible items from 1 to 9 with the Shortcut template, and pressing the
+	// number of an item selects it right away, or toggles it inside a MultiSelect. The numbers follow the visible
+	// items as the list scrolls. They take precedence over the other uses of the digits, except for:
+	//
+	// 	- the keys bound in ExtraKeys, which are called instead;
+	// 	- search mode, where the digits are typed as part of the searched term;
+	// 	- TypeAhead, where a digit typed less than a second after another character is added to the typed ones.
+	Shortcuts bool
+
+	// Stdin is the input of the select. Defaults to os.Stdin. When it is not a terminal, like a pipe, the
+	// selected item is read from a single line of it instead, holding either its label or its index. With
+	// ItemsFunc, only the items of the first page can be selected this way.
+	Stdin io.ReadCloser
+
+	// Stdout is the output of the select. Defaults to os.Stdout.
+	Stdout io.WriteCloser
+
+	// Terminal replaces the terminal of the process, using Stdin and Stdout as its input and output even if they
+	// are not terminals. Tests set it to a TestTerminal to press scripted keys in the select.
+	Terminal Terminal
+
 	label string
 
 	list *list.List
 
+	// tpls are the compiled Templates with their template functions reading the state of this select.
+	tpls selectTemplateSet
+
+	// lazy is the list loading the items with ItemsFunc, whose List is also the list of the select.
+	lazy *list.LazyList
+
+	// items are the items of all groups and itemOf maps each entry of the list to its index in items, or to
+	// NotFound for a group header. They are nil when Groups is not used.
+	items  []interface{}
+	itemOf []int
+
+	// matched are the positions of the runes highlighted by the highlight template function in the item being
+	// rendered.
+	matched []int
+
+	// checked holds the indexes of the items toggled inside a MultiSelect. It is nil for a single selection.
+	checked map[int]bool
+
+	// minChecked and maxChecked bound the number of items toggled inside a MultiSelect. A maxChecked of 0
+	// means there is no upper bound.
+	minChecked int
+	maxChecked int
+
+	// sb is the screen buffer of the running select, used by the termWidth template function.
+	sb *screenbuf.ScreenBuf
+
+	// method is how the item returned by the last run was selected, reported by RunResult.
+	method SelectMethod
+
 	// A function that determines how to render the cursor
 	Pointer Pointer
 }
 
+// SelectMethod tells how the item of a select was selected.
+type SelectMethod int
+
+const (
+	// NavigateEnter is when <Enter> selected the active item outside of search mode.
+	NavigateEnter SelectMethod = iota
+
+	// SearchEnter is when <Enter> selected the active item in search mode, ending the search at the same time.
+	SearchEnter
+
+	// Shortcut is when the item was selected by pressing its number with Shortcuts.
+	Shortcut
+
+	// Mouse is when the item was double clicked with EnableMouse.
+	Mouse
+
+	// Unattended is when no key was pressed, as when the item is read from a piped input or selected alone by
+	// AutoSelectSingle.
+	Unattended
+)
+
+// SelectResult is the outcome of a select returned by RunResult.
+type SelectResult struct {
+	// Index is the index of the selected item in Items.
+	Index int
+
+	// Item is the selected item itself, as returned by RunContext.
+	Item interface{}
+
+	// Method is how the item was selected.
+	Method SelectMethod
+}
+
 // SelectKeys defines the available keys used by select mode to enable the user to move around the list
 // and trigger search mode. See the Key struct docs for more information on keys.
 type SelectKeys struct {
@@ -95,6 +325,14 @@ type SelectKeys struct {
 
 	// Search is the key used to trigger the search mode for the list. Default to the "/" key.
 	Search Key
+
+	// Toggle is the key used to select or deselect the active item inside a MultiSelect. Defaults to the
+	// space key. It is ignored in search mode, where it is typed as part of the searched term.
+	Toggle Key
+
+	// ClearSearchKey is the key used to clear the searched term and return to the full list, keeping the cursor
+	// on the active item. Defaults to the escape key when nil.
+	ClearSearchKey *Key
 }
 
 // Key defines a keyboard code and a display representation for the help menu.
@@ -108,6 +346,27 @@ type Key struct {
 	Display string
 }
 
+// Labeler can be implemented by the items of a select to be displayed with their label by the default templates,
+// instead of their formatting with %v. The label is also returned by Run and matched with the lines piped to the
+// select.
+type Labeler interface {
+	SelectLabel() string
+}
+
+// DetailProvider can be implemented by the items of a select to display details below the list when the active
+// item changes, without a Details template. The details can have multiple lines.
+type DetailProvider interface {
+	SelectDetails() string
+}
+
+// itemLabel returns the label of the item if it implements Labeler, or the item itself.
+func itemLabel(item interface{}) interface{} {
+	if l, ok := item.(Labeler); ok {
+		return l.SelectLabel()
+	}
+	return item
+}
+
 // SelectTemplates allow a select list to be customized following stdlib
 // text/template syntax. Custom state, colors and background color are available for use inside
 // the templates and are documented inside the Variable section of the docs.
@@ -148,43 +407,161 @@ type SelectTemplates struct {
 	// Selected is a text/template for when an item was successfully selected.
 	Selected string
 
+	// Disabled is a text/template for the items that cannot be selected. Defaults to the item in faint.
+	Disabled string
+
+	// Loading is a text/template displayed below the items while the next page of items is loaded with the
+	// ItemsFunc of the select. Defaults to "Loading..." in faint.
+	Loading string
+
+	// NoResults is a text/template displayed in place of the items when none matches the searched term, which
+	// it receives. Nothing can be selected until the term matches an item. Defaults to "No results for" the
+	// quoted term, or "No results" when no term is searched.
+	NoResults string
+
+	// Header is a text/template for the header line of each group of items. It receives the name of the
+	// group. Defaults to the name in bold.
+	Header string
+
+	// Checked is a text/template for the mark displayed before the items selected inside a MultiSelect,
+	// next to the Active or Inactive template. Defaults to the IconGood.
+	Checked string
+
+	// Unchecked is a text/template for the mark displayed before the items not selected inside a
+	// MultiSelect. Defaults to a space.
+	Unchecked string
+
+	// Shortcut is a text/template for the number displayed before the visible items with Shortcuts, which it
+	// receives. The items without a number receive a space instead. Defaults to the number in faint.
+	Shortcut string
+
 	// Details is a text/template for when an item current active to show
 	// additional information. It can have multiple lines.
 	//
 	// Detail will always be displayed for the active element and thus can be used to display additional
-	// information on the element beyond its label.
+	// information on the element beyond its label. The template is given the whole active item, so any of its
+	// fields can be shown, and its number of lines can change from an item to the next: the lines left by taller
+	// details are cleared when moving to shorter ones.
 	//
 	// promptui will not trim spaces and tabs will be displayed if the template is indented.
 	Details string
 
 	// Help is a text/template for displaying instructions at the top. By default
 	// it shows keys for movement and search.
+	//
+	// Besides the keys, it receives First and Last, the positions from 1 of the first and the last visible
+	// items among the Count items matching the search, for example `{{ .First }}-{{ .Last }} of {{ .Count }}`.
 	Help string
 
+	// SearchPrompt is a text/template for the line displayed at the top instead of the Help while a term is
+	// searched. It receives the term, followed by the cursor in search mode. Defaults to the SearchPrompt
+	// variable followed by the term. The matched function tells whether any item matches, for example:
+	//
+	// 	SearchPrompt: `Search: {{ if matched }}{{ . | cyan }}{{ else }}{{ . | red }}{{ end }}`,
+	SearchPrompt string
+
 	// FuncMap is a map of helper functions that can be used inside of templates according to the text/template
 	// documentation.
 	//
 	// By default, FuncMap contains the color functions used to color the text in templates. If FuncMap
 	// is overridden, the colors functions must be added in the override from promptui.FuncMap to work.
+	//
+	// The total and matched functions are always available and return the number of items and the number of
+	// items matching the current search, for example `{{ . }} ({{ matched }}/{{ total }})` in the Label.
+	//
+	// The label function is always available too and returns the label of the items implementing Labeler, or
+	// the item itself, like in the default templates.
+	//
+	// The termWidth function is also always available and returns the number of columns a line can use without
+	// wrapping. Combined with padRight and a background color, it highlights the whole active line with a bar
+	// instead of a pointer:
+	//
+	// 	Active:   `{{ padRight (print "> " .) termWidth | bgBlue | white }}`,
+	// 	Inactive: `  {{ . }}`,
+	//
+	// The templates are compiled with the functions of FuncMap at the first run. The functions added to or
+	// replaced in the map afterwards are used once PrepareTemplates is called.
 	FuncMap template.FuncMap
 
-	label    *template.Template
-	active   *template.Template
-	inactive *template.Template
-	selected *template.Template
-	details  *template.Template
-	help     *template.Template
+	selectTemplateSet
+
+	// compiled identifies what the templates were compiled from, so they are only compiled again when it changes.
+	compiled selectTemplatesKey
 }
 
-// SearchPrompt is the prompt displayed in search mode.
+// selectTemplateSet holds the compiled templates of a select.
+type selectTemplateSet struct {
+	label     *template.Template
+	active    *template.Template
+	inactive  *template.Template
+	selected  *template.Template
+	disabled  *template.Template
+	header    *template.Template
+	loading   *template.Template
+	noResults *template.Template
+	checked   *template.Template
+	unchecked *template.Template
+	shortcut  *template.Template
+	details   *template.Template
+	help      *template.Template
+	search    *template.Template
+}
+
+// bind returns the templates of t calling funcs, like the bind of promptTemplateSet.
+func (t selectTemplateSet) bind(funcs template.FuncMap) selectTemplateSet {
+	return selectTemplateSet{
+		label:     bindTemplate(t.label, funcs),
+		active:    bindTemplate(t.active, funcs),
+		inactive:  bindTemplate(t.inactive, funcs),
+		selected:  bindTemplate(t.selected, funcs),
+		disabled:  bindTemplate(t.disabled, funcs),
+		header:    bindTemplate(t.header, funcs),
+		loading:   bindTemplate(t.loading, funcs),
+		noResults: bindTemplate(t.noResults, funcs),
+		checked:   bindTemplate(t.checked, funcs),
+		unchecked: bindTemplate(t.unchecked, funcs),
+		shortcut:  bindTemplate(t.shortcut, funcs),
+		details:   bindTemplate(t.details, funcs),
+		help:      bindTemplate(t.help, funcs),
+		search:    bindTemplate(t.search, funcs),
+	}
+}
+
+// selectTemplatesKey identifies the template strings, the FuncMap and the icons templates were compiled from,
+// like promptTemplatesKey.
+type selectTemplatesKey struct {
+	sources      [14]string
+	searchPrompt string
+	funcMap      uintptr
+	icons        IconSet
+}
+
+// SearchPrompt is the prompt displayed in search mode before the searched term by the default SearchPrompt
+// template.
 var SearchPrompt = "Search: "
 
 // Run executes the select list. It displays the label and the list of items, asking the user to chose any
 // value within to list. Run will keep the prompt alive until it has been canceled from
 // the command prompt or it has received a valid value. It will return the value and an error if any
-// occurred during the select's execution.
+// occurred during the select's execution. When there is no item, it returns ErrEmptyList right away without
+// writing anything.
 func (s *Select) Run() (int, string, error) {
-	return s.RunCursorAt(0, 0)
+	return itemString(s.projectResult(s.RunContext(context.Background())))
+}
+
+// RunContext executes the select list like Run, but also stops waiting for input when the context is done. In
+// that case, the returned error wraps the context error and matches ErrAbort when using errors.Is. It returns
+// the selected item itself rather than its string representation.
+func (s *Select) RunContext(ctx context.Context) (int, interface{}, error) {
+	return s.runCursorAt(ctx, 0, 0)
+}
+
+// RunResult executes the select list like RunContext, but returns a SelectResult also holding how the item was
+// selected. This lets callers tell apart <Enter> pressed to select the active item while searching from <Enter>
+// pressed while navigating the items, for example to skip a step of a wizard once an item is searched.
+func (s *Select) RunResult() (SelectResult, error) {
+	idx, item, err := s.RunContext(context.Background())
+	return SelectResult{Index: idx, Item: item, Method: s.method}, err
 }
 
 // RunCursorAt executes the select list, initializing the cursor to the given
@@ -193,37 +570,151 @@ func (s *Select) Run() (int, string, error) {
 // within to list. Run will keep the prompt alive until it has been canceled
 // from the command prompt or it has received a valid value. It will return
 // the value and an error if any occurred during the select's execution.
+//
+// Like Run, it returns ErrEmptyList right away when there is no item, without writing anything.
 func (s *Select) RunCursorAt(cursorPos, scroll int) (int, string, error) {
+	return itemString(s.projectResult(s.runCursorAt(context.Background(), cursorPos, scroll)))
+}
+
+// RunAndConfirm executes the select list like RunContext, then asks to confirm the chosen item with a yes or no
+// question labeled confirmLabel, as usually done before a destructive action. It returns the chosen item and
+// whether the answer is yes. The question is asked on the same input and output as the select, with its icons,
+// its color options and the FuncMap of its templates. Pressing <Ctrl+C> in the select or in the question returns
+// ErrAbort.
+func (s *Select) RunAndConfirm(confirmLabel string) (int, interface{}, bool, error) {
+	idx, item, err := s.RunContext(context.Background())
+	if err == ErrInterrupt {
+		err = ErrAbort
+	}
+	if err != nil {
+		return idx, item, false, err
+	}
+
+	confirm := Prompt{
+		Label:        confirmLabel,
+		IsVimMode:    s.IsVimMode,
+		ForceColors:  s.ForceColors,
+		PlainSuccess: s.PlainSuccess,
+		Icons:        s.Icons,
+		Stdin:        s.Stdin,
+		Stdout:       s.Stdout,
+		Terminal:     s.Terminal,
+	}
+	if s.Templates != nil && s.Templates.FuncMap != nil {
+		confirm.Templates = &PromptTemplates{FuncMap: s.Templates.FuncMap}
+	}
+
+	yes, err := confirm.RunConfirm()
+	return idx, item, yes, err
+}
+
+// projectResult replaces the item returned by a select with its label when Project is set.
+func (s *Select) projectResult(idx int, item interface{}, err error) (int, interface{}, error) {
+	if s.Project != nil && item != nil {
+		item, _ = s.Project(idx)
+	}
+	return idx, item, err
+}
+
+// itemData returns the data given to the templates of the item at the given index, which is the label returned
+// by Project when it is set, or else the item itself.
+func (s *Select) itemData(index int, item interface{}) interface{} {
+	if s.Project == nil || index == list.NotFound {
+		return item
+	}
+	label, _ := s.Project(index)
+	return label
+}
+
+// itemString returns the string representation of the item returned by a select, or an empty string if there is
+// no item, as when an error occurred.
+func itemString(idx int, item interface{}, err error) (int, string, error) {
+	if item == nil {
+		return idx, "", err
+	}
+	return idx, fmt.Sprintf("%v", itemLabel(item)), err
+}
+
+func (s *Select) runCursorAt(ctx context.Context, cursorPos, scroll int) (int, interface{}, error) {
+	if s.empty() {
+		return 0, nil, ErrEmptyList
+	}
+
 	if s.Size == 0 {
 		s.Size = 5
 	}
 
-	l, err := list.New(s.Items, s.Size)
+	l, err := s.newList()
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
 	}
-	l.Searcher = s.Searcher
 
 	s.list = l
+	s.method = NavigateEnter
+
+	if s.DefaultItem != nil {
+		cursorPos = s.defaultEntry()
+		scroll = cursorPos - s.Size + 1
+	}
 
 	s.setKeys()
 
 	err = s.prepareTemplates()
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
+	}
+
+	var idx int
+	var item interface{}
+	if s.AutoSelectSingle && s.lazy == nil && s.itemCount() == 1 && !s.isDisabled(0) {
+		idx, item, err = s.selectSingle()
+	} else {
+		idx, item, err = s.innerRun(ctx, cursorPos, scroll, ' ')
+	}
+
+	if err == nil && s.OnSelect != nil {
+		s.OnSelect(idx)
 	}
-	return s.innerRun(cursorPos, scroll, ' ')
+	return idx, item, err
 }
 
-func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error) {
-	stdin := readline.NewCancelableStdin(os.Stdin)
-	c := &readline.Config{}
+// selectSingle returns the only item of the select without reading the input, as if it was selected.
+func (s *Select) selectSingle() (int, interface{}, error) {
+	s.method = Unattended
+	item := s.item(0)
+	if !s.HideSelected {
+		s.renderSelected(pipedOutput(s.Stdout, s.ForceColors), []interface{}{s.itemData(0, item)})
+	}
+	return 0, item, nil
+}
+
+func (s *Select) innerRun(ctx context.Context, cursorPos, scroll int, top rune) (int, interface{}, error) {
+	var in io.ReadCloser = os.Stdin
+	if s.Stdin != nil {
+		in = s.Stdin
+	}
+	if s.Terminal == nil && !isTerminal(in) {
+		return s.runPiped(in, cursorPos)
+	}
+
+	c := &readline.Config{Stdout: s.Stdout}
+	configure(c, s.Terminal)
 	err := c.Init()
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
 	}
 
-	c.Stdin = stdin
+	plain := !s.ForceColors && s.Terminal == nil && !isTerminal(c.Stdout)
+	out := c.Stdout
+	c.Stdout = bellFilter{out}
+
+	var mouse *mouseReader
+	if s.EnableMouse && !plain {
+		mouse = newMouseReader(in)
+		in = mouse
+	}
+
+	c.Stdin = readline.NewCancelableStdin(escapeReader{NewKeyReader(in)})
 
 	if s.IsVimMode {
 		c.VimMode = true
@@ -234,76 +725,439 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 
 	rl, err := readline.NewEx(c)
 	if err != nil {
-		return 0, "", err
+		return 0, nil, err
 	}
 
-	rl.Write([]byte(hideCursor))
-	sb := screenbuf.New(rl, true)
+	done := make(chan struct{})
+	defer close(done)
+	closeOnDone(ctx, done, rl)
+
+	// the cursor is hidden while the select is drawn, and shown again however the select ends.
+	hidden := !plain && !s.ShowCursor
+	if hidden {
+		rl.Write([]byte(hideCursor))
+	}
+	defer func() {
+		if hidden {
+			rl.Write([]byte(showCursor))
+		}
+		rl.Close()
+	}()
 
+	// the cursor is shown again by the function above when a panic is raised again by the guard.
+	guard := &panicGuard{rl: rl}
+	if mouse != nil {
+		rl.Write([]byte(enableMouse))
+		guard.reset = disableMouse
+	}
+	defer guard.restore()
+	sb := newScreenBuf(rl, true, s.Terminal)
+	sb.Plain = plain
+	sb.ReflowOnResize = s.AutoSize
+	s.sb = sb
+
+	// rows is the height of the terminal the number of visible items was last computed for with AutoSize, and
+	// size the number of visible items before MaxLines is applied.
+	rows := 0
+	size := s.Size
+
+	// cur holds the searched term, updated and displayed on each key. The term is applied to the list apart, which
+	// SearchDebounce delays, so the typed characters are echoed right away even when the search is slow.
 	cur := NewCursor("", s.Pointer, false)
 
-	canSearch := s.Searcher != nil
+	var typed typeAhead
+
+	// vimTerm is the term confirmed with enter in vim mode, jumped to with n and N.
+	var vimTerm string
+	canSearch := s.Searcher != nil || s.Scorer != nil || s.Project != nil
 	searchMode := s.StartInSearchMode
 	s.list.SetCursor(cursorPos)
 	s.list.SetStart(scroll)
 
+	if canSearch && s.InitialSearch != "" {
+		cur.Replace(s.InitialSearch)
+		s.list.Search(s.InitialSearch)
+	}
+
+	// the list is guarded by the lock of lazy, since pages are loaded in the background, or else by mu for the
+	// debounced searches.
+	var mu sync.Mutex
+	lock := func() {
+		if s.lazy != nil {
+			s.lazy.Lock()
+		} else {
+			mu.Lock()
+		}
+	}
+	unlock := func() {
+		if s.lazy != nil {
+			s.lazy.Unlock()
+		} else {
+			mu.Unlock()
+		}
+	}
+
+	// closed is set once the select is done so pages loaded and searches debounced afterwards are not drawn.
+	closed := false
+
+	var draw func()
+
+	// search applies the searched term to the list, or only once no key was pressed for SearchDebounce. The
+	// pending search is forgotten when another one starts. It must be called with the lock held.
+	var debounce *time.Timer
+	searches := 0
+	stopSearch := func() {
+		if debounce != nil {
+			debounce.Stop()
+		}
+		searches++
+	}
+	search := func(term string) {
+		stopSearch()
+
+		if term == "" || s.SearchDebounce <= 0 {
+			if term == "" {
+				s.list.CancelSearch()
+			} else {
+				s.list.Search(term)
+			}
+			return
+		}
+
+		current := searches
+		debounce = time.AfterFunc(s.SearchDebounce, func() {
+			defer guard.catch()
+			lock()
+			defer unlock()
+			if closed || current != searches {
+				return
+			}
+			s.list.Search(term)
+			draw()
+		})
+	}
+
+	// itemsLine is the line of the first item inside the frame drawn last, followed by itemsCount items. The
+	// item and the time of the last click detect the double clicks.
+	var itemsLine, itemsCount int
+	clicked := list.NotFound
+	var clickedAt time.Time
+
+	click := func(row int) (rune, bool) {
+		line, ok := mouse.line(row)
+		if !ok {
+			return keyMouse, false
+		}
+
+		lock()
+		defer unlock()
+
+		pos := line - itemsLine
+		if pos < 0 || pos >= itemsCount {
+			return keyMouse, false
+		}
+		index := s.list.ItemIndex(pos)
+		if index == list.NotFound || s.list.Disabled != nil && s.list.Disabled(index) {
+			return keyMouse, false
+		}
+
+		double := index == clicked && time.Since(clickedAt) < doubleClickInterval
+		clicked, clickedAt = index, time.Now()
+
+		s.list.SetCursor(s.list.Start() + pos)
+		if double {
+			s.method = Mouse
+			return KeyEnter, true
+		}
+		draw()
+		return keyMouse, false
+	}
+
+	// shortcut selects the item numbered r with Shortcuts, returning KeyEnter, or toggles it inside a
+	// MultiSelect. It reports whether r was handled, which it is not in search mode or while type-ahead
+	// characters are pending.
+	shortcut := func(r rune) (rune, bool) {
+		lock()
+		defer unlock()
+
+		if searchMode || s.TypeAhead && typed.pending(time.Now()) {
+			return r, false
+		}
+
+		pos := s.shortcutPosition(r)
+		if pos == list.NotFound {
+			return r, true
+		}
+		index := s.list.ItemIndex(pos)
+		if s.list.Disabled != nil && s.list.Disabled(index) {
+			return r, true
+		}
+
+		s.list.SetCursor(s.list.Start() + pos)
+		if s.checked == nil {
+			s.method = Shortcut
+			return KeyEnter, true
+		}
+		s.toggle(s.itemIndex(s.list.Index()))
+		draw()
+		return r, true
+	}
+
+	// the extra keys are handled before readline sees them, since readline only ends on enter or an interrupt.
+	var keyErr error
+	c.FuncFilterInputRune = func(r rune) (rune, bool) {
+		defer guard.catch()
+
+		if r == keyMouse && mouse != nil {
+			ev, ok := mouse.next()
+			switch {
+			case !ok:
+				return r, false
+			case ev.action == mouseWheelUp:
+				return s.Keys.Prev.Code, true
+			case ev.action == mouseWheelDown:
+				return s.Keys.Next.Code, true
+			}
+			return click(ev.row)
+		}
+
+		// the page keys of the keyboard page like the PageUp and PageDown keys, which default to the arrows.
+		switch r {
+		case KeyPageUp:
+			return s.Keys.PageUp.Code, true
+		case KeyPageDown:
+			return s.Keys.PageDown.Code, true
+		}
+
+		// the screen is cleared and the select drawn from scratch, unless the key is one of the ExtraKeys.
+		if _, ok := s.ExtraKeys[r]; r == KeyRedraw && !ok {
+			lock()
+			defer unlock()
+
+			sb.ClearScreen()
+			draw()
+			return r, false
+		}
+
+		// the method of a selection is recorded for every enter, since the select only ends once it can submit.
+		if r == KeyEnter {
+			lock()
+			s.method = NavigateEnter
+			if searchMode {
+				s.method = SearchEnter
+			}
+			unlock()
+		}
+
+		lone := r == keyLoneEsc
+		if lone {
+			r = KeyEsc
+		}
+
+		// the searched term is cleared here too, since readline does not pass a lone escape to the listener. In
+		// vim mode, enter confirms the searched term instead of selecting, showing the full list again.
+		if canSearch && (r == s.clearSearchKey().Code || s.IsVimMode && searchMode && r == KeyEnter) {
+			lock()
+			defer unlock()
+
+			stopSearch()
+
+			// the confirmed term is searched again in case its search was debounced, so the cursor stays on the
+			// first match.
+			vimTerm = ""
+			if r == KeyEnter {
+				vimTerm = string(cur.Get())
+				if vimTerm != "" {
+					s.list.Search(vimTerm)
+				}
+			}
+
+			searchMode = false
+			cur.Replace("")
+			s.list.ClearSearch()
+			draw()
+			return r, false
+		}
+
+		fn, ok := s.ExtraKeys[r]
+		if s.Shortcuts && !ok && r >= '1' && r <= '9' {
+			if key, handled := shortcut(r); handled {
+				return key, key == KeyEnter
+			}
+		}
+		if (lone || r == KeyDelete) && !ok {
+			return r, false
+		}
+		if !ok || searchMode || s.isSelectKey(r) {
+			return r, true
+		}
+
+		lock()
+		defer unlock()
+
+		if keyErr = fn(s.cursorIndex()); keyErr != nil {
+			return readline.CharInterrupt, true
+		}
+		draw()
+		return r, false
+	}
+
 	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		defer guard.catch()
+		lock()
+		defer unlock()
+
+		// edge is set when the Next or Prev key cannot move the cursor, for the Bell.
+		edge := false
+
 		switch {
 		case key == KeyEnter:
 			return nil, 0, true
-		case key == s.Keys.Next.Code || (key == 'j' && !searchMode):
+		case key == s.Keys.Toggle.Code && s.checked != nil && !searchMode:
+			if _, idx := s.list.Items(); idx != list.NotFound && !s.list.IsDisabled() {
+				s.toggle(s.itemIndex(s.list.Index()))
+			}
+		case key == s.Keys.Next.Code || (key == 'j' && !searchMode && !s.TypeAhead):
+			index := s.list.Index()
 			s.list.Next()
-		case key == s.Keys.Prev.Code || (key == 'k' && !searchMode):
+			edge = s.list.Index() == index
+		case key == s.Keys.Prev.Code || (key == 'k' && !searchMode && !s.TypeAhead):
+			index := s.list.Index()
 			s.list.Prev()
+			edge = s.list.Index() == index
 		case key == s.Keys.Search.Code:
 			if !canSearch {
 				break
 			}
 
-			if searchMode {
-				searchMode = false
-				cur.Replace("")
-				s.list.CancelSearch()
-			} else {
-				searchMode = true
-			}
+			// the searched term stays applied when leaving search mode, so the matches can be navigated with
+			// all the keys, and can be edited again when entering search mode back.
+			searchMode = !searchMode
 		case key == KeyBackspace:
 			if !canSearch || !searchMode {
 				break
 			}
 
 			cur.Backspace()
-			if len(cur.Get()) > 0 {
-				s.list.Search(string(cur.Get()))
-			} else {
-				s.list.CancelSearch()
-			}
-		case key == s.Keys.PageUp.Code || (key == 'h' && !searchMode):
+			search(string(cur.Get()))
+		case key == s.Keys.PageUp.Code || (key == 'h' && !searchMode && !s.TypeAhead):
 			s.list.PageUp()
-		case key == s.Keys.PageDown.Code || (key == 'l' && !searchMode):
+		case key == s.Keys.PageDown.Code || (key == 'l' && !searchMode && !s.TypeAhead):
 			s.list.PageDown()
+		case s.IsVimMode && !searchMode && vimTerm != "" && (key == 'n' || key == 'N'):
+			s.list.JumpMatch(vimTerm, key == 'N')
 		default:
 			if canSearch && searchMode {
 				cur.Update(string(line))
-				s.list.Search(string(cur.Get()))
+				search(string(cur.Get()))
+			} else if s.TypeAhead && unicode.IsPrint(key) {
+				prefix, fresh := typed.add(key, time.Now())
+				s.list.Jump(s.matchPrefix(prefix), !fresh)
+			}
+		}
+
+		// the end of a lazy list is not an edge while its next page is loaded.
+		if s.lazy != nil {
+			loading := s.lazy.Load(func() {
+				defer guard.catch()
+				lock()
+				defer unlock()
+				if !closed {
+					draw()
+				}
+			})
+			edge = edge && !loading
+		}
+
+		if edge && s.Bell && !plain {
+			out.Write([]byte(bell))
+		}
+		draw()
+
+		return nil, 0, true
+	})
+
+	draw = func() {
+		if s.AutoSize && !plain {
+			if height := sb.TerminalHeight(); height != rows {
+				rows = height
+				size = s.fitSize(rows)
+				s.list.SetSize(size)
 			}
 		}
 
+		var head []byte
 		if searchMode {
-			header := SearchPrompt + cur.Format()
-			sb.WriteString(header)
+			head = render(s.tpls.search, cur.Format())
+		} else if term := cur.Get(); term != "" {
+			head = render(s.tpls.search, term)
 		} else if !s.HideHelp {
-			help := s.renderHelp(canSearch)
-			sb.Write(help)
+			head = s.renderHelp(canSearch)
 		}
 
-		label := render(s.Templates.label, s.Label)
-		sb.Write(label)
+		var label []string
+		if !s.HideLabel {
+			label = screenbuf.Wrap(string(render(s.tpls.label, s.Label)), s.wrapWidth())
+		}
 
+		if s.MaxLines > 0 {
+			s.list.SetSize(size)
+		}
 		items, idx := s.list.Items()
+
+		var details []string
+		if idx == list.NotFound {
+			details = []string{"", string(render(s.tpls.noResults, cur.Get()))}
+		} else {
+			for _, d := range s.renderDetails(s.itemIndex(s.list.Index()), items[idx]) {
+				details = append(details, screenbuf.Wrap(string(d), s.wrapWidth())...)
+			}
+		}
+
+		var footer []string
+		if s.Footer != nil {
+			line := strings.SplitN(s.Footer(s.cursorIndex()), "\n", 2)[0]
+			footer = []string{screenbuf.Truncate(line, s.termWidth(), "…")}
+		}
+
+		if s.MaxLines > 0 {
+			// the line of a lazy list loading its next page is always left, as are the visible items down to one
+			// and the footer line.
+			extra := 0
+			if s.lazy != nil {
+				extra = 1
+			}
+
+			help, labelLines, itemLines, detailLines := fitLines(s.MaxLines-len(footer), head != nil, len(label),
+				len(items)+extra, 1+extra, len(details))
+			if !help {
+				head = nil
+			}
+			label, details = label[:labelLines], details[:detailLines]
+			if itemLines -= extra; itemLines < len(items) {
+				s.list.SetSize(itemLines)
+				items, idx = s.list.Items()
+			}
+		}
+
+		if head != nil {
+			sb.Write(head)
+		}
+		for _, line := range label {
+			sb.WriteString(line)
+		}
+
+		matches := s.list.Matches()
 		last := len(items) - 1
+		itemsLine, itemsCount = sb.Cursor(), len(items)
 
+		// shortcuts counts the items numbered with Shortcuts, skipping the group headers.
+		shortcuts := 0
 		for i, item := range items {
+			if matches != nil {
+				s.matched = matches[i]
+			}
+
 			page := " "
 
 			switch i {
@@ -320,32 +1174,71 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 			}
 
 			output := []byte(page + " ")
+			index := s.itemIndex(s.list.ItemIndex(i))
 
-			if i == idx {
-				output = append(output, render(s.Templates.active, item)...)
-			} else {
-				output = append(output, render(s.Templates.inactive, item)...)
+			if header, ok := item.(groupHeader); ok {
+				output = append(output, render(s.tpls.header, header.name)...)
+				sb.Write(output)
+				s.matched = nil
+				continue
+			}
+
+			data := s.itemData(index, item)
+
+			if s.Shortcuts {
+				shortcuts++
+				key := " "
+				if shortcuts <= 9 {
+					key = strconv.Itoa(shortcuts)
+				}
+				output = append(output, render(s.tpls.shortcut, key)...)
+				output = append(output, ' ')
+			}
+
+			if s.checked != nil {
+				mark := s.tpls.unchecked
+				if s.checked[index] {
+					mark = s.tpls.checked
+				}
+				output = append(output, render(mark, data)...)
+				output = append(output, ' ')
+			}
+
+			switch {
+			case s.isDisabled(index):
+				output = append(output, render(s.tpls.disabled, data)...)
+			case i == idx:
+				output = append(output, render(s.tpls.active, data)...)
+			default:
+				output = append(output, render(s.tpls.inactive, data)...)
 			}
 
 			sb.Write(output)
 		}
+		s.matched = nil
 
-		if idx == list.NotFound {
-			sb.WriteString("")
-			sb.WriteString("No results")
-		} else {
-			active := items[idx]
-
-			details := s.renderDetails(active)
-			for _, d := range details {
-				sb.Write(d)
+		if s.lazy != nil && !s.list.CanPageDown() {
+			if s.lazy.Loading() {
+				sb.Write(render(s.tpls.loading, nil))
+			} else if err := s.lazy.Err(); err != nil {
+				sb.WriteString(fmt.Sprintf("%s %v", activeIcons(s.Icons).Bad, err))
 			}
 		}
 
+		for _, line := range details {
+			sb.WriteString(line)
+		}
+		for _, line := range footer {
+			sb.WriteString(line)
+		}
+
 		sb.Flush()
 
-		return nil, 0, true
-	})
+		if mouse != nil {
+			mouse.query(sb.Height())
+			rl.Write([]byte(queryCursor))
+		}
+	}
 
 	for {
 		_, err = rl.Readline()
@@ -360,11 +1253,36 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 			break
 		}
 
-		_, idx := s.list.Items()
-		if idx != list.NotFound {
+		lock()
+		submit := s.canSubmit()
+		unlock()
+
+		if submit {
 			break
 		}
+	}
+	guard.check()
 
+	if mouse != nil {
+		rl.Write([]byte(disableMouse))
+	}
+
+	lock()
+	closed = true
+	defer unlock()
+
+	if err != nil && ctx.Err() != nil {
+		err = &contextError{err: ctx.Err()}
+	}
+
+	if err != nil && keyErr != nil {
+		clearScreen(sb)
+
+		idx := s.cursorIndex()
+		if idx == list.NotFound {
+			return idx, nil, keyErr
+		}
+		return idx, s.item(idx), keyErr
 	}
 
 	if err != nil {
@@ -373,27 +1291,298 @@ func (s *Select) innerRun(cursorPos, scroll int, top rune) (int, string, error)
 		}
 		sb.Reset()
 		sb.WriteString("")
-		sb.Flush()
-		rl.Write([]byte(showCursor))
-		rl.Close()
-		return 0, "", err
+		sb.FlushFinal()
+		return 0, nil, err
+	}
+
+	if s.checked != nil {
+		s.renderSelected(sb, s.checkedData())
+		return 0, nil, nil
 	}
 
 	items, idx := s.list.Items()
 	item := items[idx]
 
-	if s.HideSelected {
+	s.renderSelected(sb, []interface{}{s.itemData(s.itemIndex(s.list.Index()), item)})
+	return s.itemIndex(s.list.Index()), item, err
+}
+
+// renderSelected displays the selected items in place of the select. The select is cleared entirely instead when
+// HideSelected is set or the Selected template renders nothing, so no empty line is left behind. The selection is
+// also appended to the TranscriptWriter.
+func (s *Select) renderSelected(sb *screenbuf.ScreenBuf, items []interface{}) {
+	labels := make([]string, len(items))
+	for i, item := range items {
+		labels[i] = fmt.Sprintf("%v", itemLabel(item))
+	}
+	transcribe(s.TranscriptWriter, s.Label, strings.Join(labels, ", "))
+
+	var lines [][]byte
+	for _, item := range items {
+		line := render(s.tpls.selected, item)
+		if s.PlainSuccess {
+			line = screenbuf.StripANSI(line)
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	if s.HideSelected || len(lines) == 0 {
 		clearScreen(sb)
+		return
+	}
+
+	sb.Reset()
+	for _, line := range lines {
+		sb.Write(line)
+	}
+	sb.FlushFinal()
+}
+
+// defaultEntry returns the position inside the list of the first item equal to the DefaultItem, or 0 if there
+// is none.
+func (s *Select) defaultEntry() int {
+	for i := 0; i < s.itemCount(); i++ {
+		if !reflect.DeepEqual(s.item(i), s.DefaultItem) {
+			continue
+		}
+
+		for entry, index := range s.itemOf {
+			if index == i {
+				return entry
+			}
+		}
+		return i
+	}
+	return 0
+}
+
+// total returns the number of items of the select, for the total template function.
+func (s *Select) total() int {
+	return s.itemCount()
+}
+
+// fitSize returns the number of items fitting in the given number of terminal rows along with the help, the
+// label and the details of the active item. The last row is left for the cursor below the select.
+func (s *Select) fitSize(rows int) int {
+	// the help or the searched term takes a line, as does the cursor.
+	overhead := 2
+	if !s.HideLabel {
+		label := render(s.tpls.label, s.Label)
+		overhead += len(screenbuf.Wrap(string(label), s.wrapWidth()))
+	}
+	if s.lazy != nil {
+		overhead++
+	}
+	if s.Footer != nil {
+		overhead++
+	}
+
+	items, idx := s.list.Items()
+	if idx == list.NotFound {
+		// the line of the NoResults template follows an empty line.
+		overhead += 2
 	} else {
-		sb.Reset()
-		sb.Write(render(s.Templates.selected, item))
-		sb.Flush()
+		for _, d := range s.renderDetails(s.cursorIndex(), items[idx]) {
+			overhead += len(screenbuf.Wrap(string(d), s.wrapWidth()))
+		}
+	}
+
+	if size := rows - overhead; size > 1 {
+		return size
+	}
+	return 1
+}
+
+// fitLines cuts the parts of a frame so it has at most max lines, returning whether the help line is kept and the
+// number of lines left to the label, the items and the details. The details are truncated first, then the help
+// line is dropped, then the items are reduced down to minItems, and the label is truncated last.
+func fitLines(max int, help bool, label, items, minItems, details int) (bool, int, int, int) {
+	over := label + items + details - max
+	if help {
+		over++
+	}
+
+	cut := func(n, min int) int {
+		c := over
+		if c > n-min {
+			c = n - min
+		}
+		if c < 0 {
+			return n
+		}
+		over -= c
+		return n - c
+	}
+
+	details = cut(details, 0)
+	if help && over > 0 {
+		help = false
+		over--
+	}
+	items = cut(items, minItems)
+	label = cut(label, 0)
+	return help, label, items, details
+}
+
+// termWidth returns the number of columns a line of the select can use. The last column of the terminal is left
+// empty so a line padded to this width never wraps.
+func (s *Select) termWidth() int {
+	width := screenbuf.DefaultWidth
+	if s.sb != nil {
+		width = s.sb.Width()
+	}
+	return width - 1
+}
+
+// wrapWidth returns the number of columns the label and the details are wrapped to.
+func (s *Select) wrapWidth() int {
+	if s.WrapWidth > 0 {
+		return s.WrapWidth
+	}
+	return s.termWidth()
+}
+
+// matchedCount returns the number of items matching the current search, for the matched template function.
+func (s *Select) matchedCount() int {
+	if s.list == nil {
+		return 0
+	}
+
+	if s.itemOf == nil {
+		return s.list.MatchedLen()
+	}
+
+	n := 0
+	for _, entry := range s.list.Matched() {
+		if s.itemOf[entry] != list.NotFound {
+			n++
+		}
+	}
+	return n
+}
+
+// highlightStyle is the style applied by the highlight template function to the matching runes.
+var highlightStyle = Styler(FGBold, FGUnderline)
+
+// highlight styles the runes of v at the positions matched by the current search for the item being rendered.
+func (s *Select) highlight(v interface{}) string {
+	str := fmt.Sprintf("%v", itemLabel(v))
+	if len(s.matched) == 0 {
+		return str
+	}
+
+	matched := map[int]bool{}
+	for _, i := range s.matched {
+		matched[i] = true
+	}
+
+	var out, run strings.Builder
+	for i, r := range []rune(str) {
+		if matched[i] {
+			run.WriteRune(r)
+			continue
+		}
+		if run.Len() > 0 {
+			out.WriteString(highlightStyle(run.String()))
+			run.Reset()
+		}
+		out.WriteRune(r)
+	}
+	if run.Len() > 0 {
+		out.WriteString(highlightStyle(run.String()))
+	}
+	return out.String()
+}
+
+// canSubmit reports whether the select can return the active item, or the selected items of a MultiSelect.
+func (s *Select) canSubmit() bool {
+	if s.checked != nil {
+		return len(s.checked) >= s.minChecked
 	}
 
-	rl.Write([]byte(showCursor))
-	rl.Close()
+	_, idx := s.list.Items()
+	return idx != list.NotFound && !s.list.IsDisabled()
+}
+
+// cursorIndex returns the index of the item under the cursor, or list.NotFound if there is none.
+func (s *Select) cursorIndex() int {
+	if _, idx := s.list.Items(); idx == list.NotFound {
+		return list.NotFound
+	}
+	return s.itemIndex(s.list.Index())
+}
+
+// isSelectKey reports whether r is one of the keys of the select, which cannot be used by ExtraKeys.
+func (s *Select) isSelectKey(r rune) bool {
+	switch r {
+	case KeyEnter, KeyBackspace, s.Keys.Next.Code, s.Keys.Prev.Code, s.Keys.PageUp.Code, s.Keys.PageDown.Code,
+		s.Keys.Search.Code, s.clearSearchKey().Code:
+		return true
+	}
+	return s.checked != nil && r == s.Keys.Toggle.Code
+}
 
-	return s.list.Index(), fmt.Sprintf("%v", item), err
+// isDisabled reports whether the item at the given index of Items cannot be selected.
+func (s *Select) isDisabled(index int) bool {
+	return s.Disabled != nil && s.Disabled(index)
+}
+
+// typeAheadTimeout is the delay after which the characters typed in TypeAhead mode are forgotten.
+const typeAheadTimeout = time.Second
+
+// typeAhead accumulates the characters typed in TypeAhead mode.
+type typeAhead struct {
+	prefix string
+	last   time.Time
+}
+
+// add appends r to the typed characters, starting over if the previous character was typed more than
+// typeAheadTimeout ago. It returns the typed characters and whether they were started over.
+func (t *typeAhead) add(r rune, now time.Time) (string, bool) {
+	fresh := now.Sub(t.last) > typeAheadTimeout
+	if fresh {
+		t.prefix = ""
+	}
+	t.prefix += string(r)
+	t.last = now
+	return t.prefix, fresh
+}
+
+// pending reports whether characters were typed less than typeAheadTimeout ago, so the next one is added to them.
+func (t *typeAhead) pending(now time.Time) bool {
+	return t.prefix != "" && now.Sub(t.last) <= typeAheadTimeout
+}
+
+// shortcutPosition returns the position among the visible items of the item numbered with the digit r by
+// Shortcuts, or list.NotFound if there is none. The group headers are not numbered.
+func (s *Select) shortcutPosition(r rune) int {
+	if r < '1' || r > '9' {
+		return list.NotFound
+	}
+
+	n := int(r - '0')
+	items, _ := s.list.Items()
+	for i, item := range items {
+		if _, ok := item.(groupHeader); ok {
+			continue
+		}
+		if n--; n == 0 {
+			return i
+		}
+	}
+	return list.NotFound
+}
+
+// matchPrefix returns a function reporting whether an item, as displayed by the Inactive template, starts
+// with prefix. The comparison ignores the case.
+func (s *Select) matchPrefix(prefix string) func(item interface{}) bool {
+	prefix = strings.ToLower(prefix)
+	return func(item interface{}) bool {
+		label := string(screenbuf.StripANSI(render(s.tpls.inactive, item)))
+		return strings.HasPrefix(strings.TrimSpace(strings.ToLower(label)), prefix)
+	}
 }
 
 // ScrollPosition returns the current scroll position.
@@ -401,6 +1590,18 @@ func (s *Select) ScrollPosition() int {
 	return s.list.Start()
 }
 
+// PrepareTemplates compiles the templates of the select, and returns the error of an invalid template. Like for a
+// prompt, the templates are kept compiled for the next runs, and Run only compiles them again once the template
+// strings, the FuncMap, the icons or SearchPrompt are replaced. Calling PrepareTemplates compiles them again after
+// functions were added to or replaced in their FuncMap. Once compiled, the templates can be shared by selects
+// running at the same time.
+func (s *Select) PrepareTemplates() error {
+	if s.Templates != nil {
+		s.Templates.label = nil
+	}
+	return s.prepareTemplates()
+}
+
 func (s *Select) prepareTemplates() error {
 	tpls := s.Templates
 	if tpls == nil {
@@ -411,51 +1612,167 @@ func (s *Select) prepareTemplates() error {
 		tpls.FuncMap = FuncMap
 	}
 
-	if tpls.Label == "" {
-		tpls.Label = fmt.Sprintf("%s {{.}}: ", IconInitial)
+	s.Templates = tpls
+
+	if tpls.label == nil || tpls.compiled != s.templatesKey(tpls) {
+		if err := s.compileTemplates(tpls); err != nil {
+			return err
+		}
+	}
+
+	s.tpls = tpls.selectTemplateSet.bind(s.stateFuncs())
+
+	return nil
+}
+
+// stateFuncs returns the template functions reading the state of the select while it runs.
+func (s *Select) stateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"highlight": s.highlight,
+		"total":     s.total,
+		"matched":   s.matchedCount,
+		"termWidth": s.termWidth,
+	}
+}
+
+// compileTemplates compiles the templates of tpls. Like for a prompt, the defaults of the empty template strings
+// are not saved in tpls, so they are compiled again for the SearchPrompt of the next runs.
+func (s *Select) compileTemplates(tpls *SelectTemplates) error {
+	tpls.compiled = selectTemplatesKey{}
+
+	funcs := s.stateFuncs()
+	funcs["label"] = itemLabel
+	for name, fn := range withIcons(tpls.FuncMap, s.Icons) {
+		funcs[name] = fn
+	}
+
+	source := tpls.Label
+	if source == "" {
+		source = "{{ iconInitial }} {{.}}: "
 	}
 
-	tpl, err := template.New("").Funcs(tpls.FuncMap).Parse(tpls.Label)
+	tpl, err := template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.label = tpl
 
-	if tpls.Active == "" {
-		tpls.Active = fmt.Sprintf("%s {{ . | underline }}", IconSelect)
+	source = tpls.Active
+	if source == "" {
+		source = "{{ iconSelect }} {{ label . | underline }}"
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Active)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.active = tpl
 
-	if tpls.Inactive == "" {
-		tpls.Inactive = "  {{.}}"
+	source = tpls.Inactive
+	if source == "" {
+		source = "  {{ label . }}"
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Inactive)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.inactive = tpl
 
-	if tpls.Selected == "" {
-		tpls.Selected = fmt.Sprintf(`{{ "%s" | green }} {{ . | faint }}`, IconGood)
+	source = tpls.Selected
+	if source == "" {
+		source = `{{ iconGood | green }} {{ label . | faint }}`
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Selected)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 	tpls.selected = tpl
 
+	source = tpls.Disabled
+	if source == "" {
+		source = "  {{ label . | faint }}"
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.disabled = tpl
+
+	source = tpls.Loading
+	if source == "" {
+		source = `{{ "Loading..." | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.loading = tpl
+
+	source = tpls.NoResults
+	if source == "" {
+		source = `{{ if . }}No results for {{ printf "%q" . }}{{ else }}No results{{ end }}`
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.noResults = tpl
+
+	source = tpls.Header
+	if source == "" {
+		source = "{{ . | bold }}"
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.header = tpl
+
+	source = tpls.Checked
+	if source == "" {
+		source = "{{ iconGood }}"
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.checked = tpl
+
+	source = tpls.Unchecked
+	if source == "" {
+		source = " "
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.unchecked = tpl
+
+	source = tpls.Shortcut
+	if source == "" {
+		source = `{{ . | faint }}`
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+	tpls.shortcut = tpl
+
+	tpls.details = nil
 	if tpls.Details != "" {
-		tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Details)
+		tpl, err = template.New("").Funcs(funcs).Parse(tpls.Details)
 		if err != nil {
 			return err
 		}
@@ -463,24 +1780,49 @@ func (s *Select) prepareTemplates() error {
 		tpls.details = tpl
 	}
 
-	if tpls.Help == "" {
-		tpls.Help = fmt.Sprintf(`{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
+	source = tpls.Help
+	if source == "" {
+		source = fmt.Sprintf(`{{ "Use the arrow keys to navigate:" | faint }} {{ .NextKey | faint }} ` +
 			`{{ .PrevKey | faint }} {{ .PageDownKey | faint }} {{ .PageUpKey | faint }} ` +
-			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}`)
+			`{{ if .Search }} {{ "and" | faint }} {{ .SearchKey | faint }} {{ "toggles search" | faint }}{{ end }}` +
+			`{{ if .Toggle }} {{ .ToggleKey | faint }} {{ "selects" | faint }}{{ end }}`)
 	}
 
-	tpl, err = template.New("").Funcs(tpls.FuncMap).Parse(tpls.Help)
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
 	if err != nil {
 		return err
 	}
 
 	tpls.help = tpl
 
-	s.Templates = tpls
+	source = tpls.SearchPrompt
+	if source == "" {
+		source = SearchPrompt + "{{ . }}"
+	}
+
+	tpl, err = template.New("").Funcs(funcs).Parse(source)
+	if err != nil {
+		return err
+	}
+
+	tpls.search = tpl
+	tpls.compiled = s.templatesKey(tpls)
 
 	return nil
 }
 
+// templatesKey returns the key of the templates compiled from tpls for the select.
+func (s *Select) templatesKey(tpls *SelectTemplates) selectTemplatesKey {
+	return selectTemplatesKey{
+		sources: [14]string{tpls.Label, tpls.Active, tpls.Inactive, tpls.Selected, tpls.Disabled, tpls.Loading,
+			tpls.NoResults, tpls.Header, tpls.Checked, tpls.Unchecked, tpls.Shortcut, tpls.Details, tpls.Help,
+			tpls.SearchPrompt},
+		searchPrompt: SearchPrompt,
+		funcMap:      reflect.ValueOf(tpls.FuncMap).Pointer(),
+		icons:        activeIcons(s.Icons),
+	}
+}
+
 // SelectWithAdd represents a list for selecting a single item inside a list of items with the possibility to
 // add new items to the list.
 type SelectWithAdd struct {
@@ -509,6 +1851,15 @@ type SelectWithAdd struct {
 
 	// HideHelp sets whether to hide help information.
 	HideHelp bool
+
+	// AppendAdded appends the added value to Items once it is valid, so the next Run lists it.
+	AppendAdded bool
+
+	// Stdin, Stdout and Terminal are the input, the output and the terminal of both the list and the add item
+	// prompt, like the fields of Select.
+	Stdin    io.ReadCloser
+	Stdout   io.WriteCloser
+	Terminal Terminal
 }
 
 // Run executes the select list. Its displays the label and the list of items, asking the user to chose any
@@ -517,7 +1868,8 @@ type SelectWithAdd struct {
 //
 // If the addLabel is selected in the list, this function will return a -1 index with the added label and no error.
 // Otherwise, it will return the index and the value of the selected item. In any case, if an error is triggered, it
-// will also return the error as its third return value.
+// will also return the error as its third return value. Pressing <Ctrl+C> in the list or in the prompt returns
+// ErrInterrupt.
 func (sa *SelectWithAdd) Run() (int, string, error) {
 	if len(sa.Items) > 0 {
 		newItems := append([]string{sa.AddLabel}, sa.Items...)
@@ -535,6 +1887,9 @@ func (sa *SelectWithAdd) Run() (int, string, error) {
 			Size:      5,
 			list:      list,
 			Pointer:   sa.Pointer,
+			Stdin:     sa.Stdin,
+			Stdout:    sa.Stdout,
+			Terminal:  sa.Terminal,
 		}
 		s.setKeys()
 
@@ -543,13 +1898,19 @@ func (sa *SelectWithAdd) Run() (int, string, error) {
 			return 0, "", err
 		}
 
-		selected, value, err := s.innerRun(1, 0, '+')
+		selected, value, err := itemString(s.innerRun(context.Background(), 1, 0, '+'))
 		if err != nil || selected != 0 {
 			return selected - 1, value, err
 		}
 
-		// XXX run through terminal for windows
-		os.Stdout.Write([]byte(upLine(1) + "\r" + clearLine))
+		// the line of the selected add label is replaced by the prompt.
+		var out io.Writer = os.Stdout
+		if sa.Stdout != nil {
+			out = sa.Stdout
+		}
+		if sa.Terminal != nil || isTerminal(out) {
+			out.Write([]byte(upLine(1) + "\r" + clearLine))
+		}
 	}
 
 	p := Prompt{
@@ -557,11 +1918,25 @@ func (sa *SelectWithAdd) Run() (int, string, error) {
 		Validate:  sa.Validate,
 		IsVimMode: sa.IsVimMode,
 		Pointer:   sa.Pointer,
+		Stdin:     sa.Stdin,
+		Stdout:    sa.Stdout,
+		Terminal:  sa.Terminal,
 	}
 	value, err := p.Run()
+	if err == nil && sa.AppendAdded {
+		sa.Items = append(sa.Items, value)
+	}
 	return SelectedAdd, value, err
 }
 
+// clearSearchKey returns the key clearing the searched term, which is the escape key unless set in the Keys.
+func (s *Select) clearSearchKey() Key {
+	if s.Keys.ClearSearchKey == nil {
+		return Key{Code: KeyEsc, Display: KeyEscDisplay}
+	}
+	return *s.Keys.ClearSearchKey
+}
+
 func (s *Select) setKeys() {
 	if s.Keys != nil {
 		return
@@ -572,20 +1947,38 @@ func (s *Select) setKeys() {
 		PageUp:   Key{Code: KeyBackward, Display: KeyBackwardDisplay},
 		PageDown: Key{Code: KeyForward, Display: KeyForwardDisplay},
 		Search:   Key{Code: '/', Display: "/"},
+		Toggle:   Key{Code: ' ', Display: "space"},
 	}
 }
 
-func (s *Select) renderDetails(item interface{}) [][]byte {
-	if s.Templates.details == nil {
-		return nil
+func (s *Select) renderDetails(index int, item interface{}) [][]byte {
+	projected := s.Project != nil && index != list.NotFound
+
+	if s.tpls.details == nil {
+		var details string
+		if projected {
+			_, details = s.Project(index)
+		} else if d, ok := item.(DetailProvider); ok {
+			details = d.SelectDetails()
+		}
+
+		if details == "" {
+			return nil
+		}
+		return bytes.Split([]byte(details), []byte("\n"))
+	}
+
+	data := item
+	if projected {
+		_, data = s.Project(index)
 	}
 
 	var buf bytes.Buffer
 	w := ansiterm.NewTabWriter(&buf, 0, 0, 8, ' ', 0)
 
-	err := s.Templates.details.Execute(w, item)
+	err := s.tpls.details.Execute(w, data)
 	if err != nil {
-		fmt.Fprintf(w, "%v", item)
+		fmt.Fprintf(w, "%v", data)
 	}
 
 	w.Flush()
@@ -603,6 +1996,12 @@ func (s *Select) renderHelp(b bool) []byte {
 		PageUpKey   string
 		Search      bool
 		SearchKey   string
+		ClearKey    string
+		Toggle      bool
+		ToggleKey   string
+		First       int
+		Last        int
+		Count       int
 	}{
 		NextKey:     s.Keys.Next.Display,
 		PrevKey:     s.Keys.Prev.Display,
@@ -610,9 +2009,43 @@ func (s *Select) renderHelp(b bool) []byte {
 		PageUpKey:   s.Keys.PageUp.Display,
 		SearchKey:   s.Keys.Search.Display,
 		Search:      b,
+		ClearKey:    s.clearSearchKey().Display,
+		Toggle:      s.checked != nil,
+		ToggleKey:   s.Keys.Toggle.Display,
+		Count:       s.matchedCount(),
+	}
+	keys.First, keys.Last = s.visibleRange()
+
+	return render(s.tpls.help, keys)
+}
+
+// visibleRange returns the positions from 1 of the first and the last visible items among the matched items, or
+// 0 and 0 if none is visible. The headers of the groups are not counted.
+func (s *Select) visibleRange() (int, int) {
+	items, _ := s.list.Items()
+	start := s.list.Start()
+	if s.itemOf == nil {
+		if len(items) == 0 {
+			return 0, 0
+		}
+		return start + 1, start + len(items)
 	}
 
-	return render(s.Templates.help, keys)
+	before, visible := 0, 0
+	for i, entry := range s.list.Matched() {
+		if s.itemOf[entry] == list.NotFound {
+			continue
+		}
+		if i < start {
+			before++
+		} else if i < start+len(items) {
+			visible++
+		}
+	}
+	if visible == 0 {
+		return 0, 0
+	}
+	return before + 1, before + visible
 }
 
 func render(tpl *template.Template, data interface{}) []byte {
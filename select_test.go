@@ -2,9 +2,18 @@ package promptui
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"text/template"
+	"time"
 
-	"github.com/manifoldco/promptui/screenbuf"
+	"github.com/logrhythm/promptui/list"
+	"github.com/logrhythm/promptui/screenbuf"
 )
 
 func TestSelectTemplateRender(t *testing.T) {
@@ -42,6 +51,12 @@ func TestSelectTemplateRender(t *testing.T) {
 		if result != exp {
 			t.Errorf("Expected selected item to eq %q, got %q", exp, result)
 		}
+
+		result = string(render(s.Templates.disabled, values[0]))
+		exp = "  \x1b[2mZero\x1b[0m"
+		if result != exp {
+			t.Errorf("Expected disabled item to eq %q, got %q", exp, result)
+		}
 	})
 
 	t.Run("when using custom style", func(t *testing.T) {
@@ -155,6 +170,7 @@ Description: {{.Description}}`,
 func TestClearScreen(t *testing.T) {
 	var buf bytes.Buffer
 	sb := screenbuf.New(&buf, false)
+	sb.SetWidth(80)
 
 	sb.WriteString("test")
 	clearScreen(sb)
@@ -166,3 +182,1661 @@ func TestClearScreen(t *testing.T) {
 		t.Errorf("expected %q, got %q", except, got)
 	}
 }
+
+func TestSelectTypeAhead(t *testing.T) {
+	t.Run("accumulates typed characters", func(t *testing.T) {
+		var typed typeAhead
+		now := time.Now()
+
+		prefix, fresh := typed.add('g', now)
+		if prefix != "g" || !fresh {
+			t.Errorf("Expected a fresh 'g', got %q, %v", prefix, fresh)
+		}
+
+		prefix, fresh = typed.add('o', now.Add(typeAheadTimeout/2))
+		if prefix != "go" || fresh {
+			t.Errorf("Expected 'go', got %q, %v", prefix, fresh)
+		}
+
+		prefix, fresh = typed.add('x', now.Add(2*typeAheadTimeout))
+		if prefix != "x" || !fresh {
+			t.Errorf("Expected a fresh 'x' after the timeout, got %q, %v", prefix, fresh)
+		}
+	})
+
+	t.Run("matches the displayed label", func(t *testing.T) {
+		s := Select{
+			Items:     []string{"Zero"},
+			Templates: &SelectTemplates{Inactive: "  {{ . | bold }}"},
+		}
+		err := s.prepareTemplates()
+		if err != nil {
+			t.Fatalf("Unexpected error preparing templates %v", err)
+		}
+
+		if !s.matchPrefix("ze")("Zero") {
+			t.Errorf("Expected 'Zero' to match 'ze'")
+		}
+
+		if s.matchPrefix("ro")("Zero") {
+			t.Errorf("Expected 'Zero' not to match 'ro'")
+		}
+	})
+}
+
+func TestSelectDefaultItem(t *testing.T) {
+	type pepper struct {
+		Name     string
+		HeatUnit int
+	}
+
+	tcs := []struct {
+		scenario string
+		s        Select
+		expected int
+	}{
+		{
+			scenario: "when matching a string",
+			s:        Select{Items: []string{"a", "b", "c"}, DefaultItem: "c"},
+			expected: 2,
+		},
+		{
+			scenario: "when matching a struct",
+			s: Select{
+				Items:       []pepper{{Name: "Bell Pepper"}, {Name: "Habanero", HeatUnit: 100000}},
+				DefaultItem: pepper{Name: "Habanero", HeatUnit: 100000},
+			},
+			expected: 1,
+		},
+		{
+			scenario: "when no item matches",
+			s:        Select{Items: []string{"a", "b", "c"}, DefaultItem: "d"},
+			expected: 0,
+		},
+		{
+			scenario: "when using groups",
+			s: Select{
+				Groups: []SelectGroup{
+					{Name: "Fruits", Items: []string{"apple"}},
+					{Name: "Vegetables", Items: []string{"carrot", "pea"}},
+				},
+				DefaultItem: "pea",
+			},
+			expected: 4,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := tc.s
+			s.Size = 5
+			if _, err := s.newList(); err != nil {
+				t.Fatalf("Unexpected error creating the list %v", err)
+			}
+
+			if got := s.defaultEntry(); got != tc.expected {
+				t.Errorf("Expected the cursor at %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSelectHighlight(t *testing.T) {
+	s := Select{
+		Items:     []string{"Bell Pepper"},
+		Templates: &SelectTemplates{Inactive: "{{ . | highlight }}"},
+	}
+	err := s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	result := string(render(s.Templates.inactive, "Bell Pepper"))
+	if result != "Bell Pepper" {
+		t.Errorf("Expected no highlight without matches, got %q", result)
+	}
+
+	s.matched = []int{0, 5, 6}
+	result = string(render(s.Templates.inactive, "Bell Pepper"))
+	exp := "\x1b[1;4mB\x1b[0mell \x1b[1;4mPe\x1b[0mpper"
+	if result != exp {
+		t.Errorf("Expected highlighted item to eq %q, got %q", exp, result)
+	}
+}
+
+func TestSelectCounts(t *testing.T) {
+	s := Select{
+		Label:     "Fruit",
+		Items:     []string{"apple", "banana", "cherry"},
+		Size:      5,
+		Searcher:  NewStringSearcher([]string{"apple", "banana", "cherry"}),
+		Templates: &SelectTemplates{Label: "{{ . }} ({{ matched }}/{{ total }})"},
+	}
+
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error creating the list %v", err)
+	}
+	s.list = l
+
+	err = s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	s.list.Search("an")
+	result := string(render(s.tpls.label, s.Label))
+	if result != "Fruit (1/3)" {
+		t.Errorf("Expected label to eq %q, got %q", "Fruit (1/3)", result)
+	}
+}
+
+func TestSelectSharedTemplates(t *testing.T) {
+	tpls := &SelectTemplates{Label: "{{ . }} ({{ total }})", Selected: "{{ . }}"}
+	if err := (&Select{Templates: tpls}).PrepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, items := range [][]string{{"apple"}, {"apple", "banana"}, {"apple", "banana", "cherry"}} {
+		wg.Add(1)
+		go func(items []string) {
+			defer wg.Done()
+
+			term := NewTestTerminal("\r")
+			s := Select{Label: "Fruit", Items: items, Templates: tpls, Terminal: term, Stdin: term, Stdout: term}
+			if _, _, err := s.Run(); err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+			exp := fmt.Sprintf("Fruit (%d)", len(items))
+			if !strings.Contains(term.Output(), exp) {
+				t.Errorf("Expected %q in the output, got %q", exp, term.Output())
+			}
+		}(items)
+	}
+	wg.Wait()
+}
+
+func TestSelectItemsFunc(t *testing.T) {
+	s := Select{
+		Size: 2,
+		ItemsFunc: func(offset, limit int) ([]interface{}, bool, error) {
+			return []interface{}{"a", "b", "c"}[offset:], false, nil
+		},
+		DefaultItem: "b",
+	}
+
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error creating the list %v", err)
+	}
+	s.list = l
+
+	if s.itemCount() != 3 || s.item(2) != "c" {
+		t.Errorf("Expected the loaded items, got %d items", s.itemCount())
+	}
+
+	if got := s.defaultEntry(); got != 1 {
+		t.Errorf("Expected the cursor at 1, got %d", got)
+	}
+
+	s.Groups = []SelectGroup{{Name: "Letters", Items: []string{"a"}}}
+	if _, err := s.newList(); err == nil {
+		t.Errorf("Expected error with groups got none")
+	}
+}
+
+func TestItemString(t *testing.T) {
+	type pepper struct{ Name string }
+
+	idx, value, err := itemString(1, pepper{Name: "Habanero"}, nil)
+	if idx != 1 || value != "{Habanero}" || err != nil {
+		t.Errorf("Expected 1, {Habanero} and no error, got %d, %q and %v", idx, value, err)
+	}
+
+	_, value, err = itemString(0, nil, ErrInterrupt)
+	if value != "" || err != ErrInterrupt {
+		t.Errorf("Expected an empty value and ErrInterrupt, got %q and %v", value, err)
+	}
+
+	errDelete := errors.New("delete")
+	idx, value, err = itemString(2, "Jalapeno", errDelete)
+	if idx != 2 || value != "Jalapeno" || err != errDelete {
+		t.Errorf("Expected 2, Jalapeno and the key error, got %d, %q and %v", idx, value, err)
+	}
+}
+
+func TestSelectExtraKeys(t *testing.T) {
+	items := []string{"a", "b"}
+	s := Select{Items: items, Size: 5, Searcher: NewStringSearcher(items)}
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.list = l
+	s.setKeys()
+
+	s.list.Next()
+	if idx := s.cursorIndex(); idx != 1 {
+		t.Errorf("Expected the cursor on item 1, got %d", idx)
+	}
+
+	s.list.Search("z")
+	if idx := s.cursorIndex(); idx != list.NotFound {
+		t.Errorf("Expected no item under the cursor, got %d", idx)
+	}
+
+	for _, r := range []rune{KeyEnter, KeyNext, KeyPrev, '/'} {
+		if !s.isSelectKey(r) {
+			t.Errorf("Expected %q to be a key of the select", r)
+		}
+	}
+	for _, r := range []rune{'d', 'j', ' '} {
+		if s.isSelectKey(r) {
+			t.Errorf("Expected %q not to be a key of the select", r)
+		}
+	}
+}
+
+func TestSelectTermWidth(t *testing.T) {
+	s := Select{
+		Label: "Pick",
+		Items: []string{"a", "日本"},
+		Templates: &SelectTemplates{
+			Active: `{{ padRight (print "> " .) termWidth }}`,
+		},
+	}
+
+	err := s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s.sb = screenbuf.New(&bytes.Buffer{}, true)
+	s.sb.SetWidth(10)
+
+	tcs := []struct {
+		item     string
+		expected string
+	}{
+		{"a", "> a      "},
+		{"日本", "> 日本   "},
+	}
+	for _, tc := range tcs {
+		got := string(render(s.Templates.active, tc.item))
+		if got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestSelectRenderSelected(t *testing.T) {
+	tcs := []struct {
+		scenario     string
+		s            Select
+		expectedLine bool
+	}{
+		{"when displaying the selected item", Select{}, true},
+		{"when hiding the selected item", Select{HideSelected: true}, false},
+		{"when the selected template is empty", Select{Templates: &SelectTemplates{Selected: "{{ if false }}{{ end }}"}}, false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := tc.s
+			err := s.prepareTemplates()
+			if err != nil {
+				t.Fatalf("Unexpected error preparing templates %v", err)
+			}
+
+			var buf bytes.Buffer
+			sb := screenbuf.New(&buf, true)
+			sb.WriteString("help")
+			sb.WriteString("item")
+			sb.Flush()
+			buf.Reset()
+
+			s.renderSelected(sb, []interface{}{"pepper"})
+
+			got := buf.String()
+			if tc.expectedLine != strings.Contains(got, "pepper") {
+				t.Errorf("Unexpected selected line in %q", got)
+			}
+			if !tc.expectedLine && got != strings.Repeat("\x1b[1A\x1b[2K\r", 2) {
+				t.Errorf("Expected both lines to be cleared, got %q", got)
+			}
+		})
+	}
+}
+
+func TestSelectWrapWidth(t *testing.T) {
+	s := Select{}
+	if w := s.wrapWidth(); w != screenbuf.DefaultWidth-1 {
+		t.Errorf("Expected the terminal width, got %d", w)
+	}
+
+	s.WrapWidth = 20
+	if w := s.wrapWidth(); w != 20 {
+		t.Errorf("Expected the wrap width, got %d", w)
+	}
+}
+
+func TestSelectClearSearchKey(t *testing.T) {
+	s := Select{Items: []string{"a"}}
+	s.setKeys()
+
+	if k := s.clearSearchKey(); k.Code != KeyEsc || k.Display != KeyEscDisplay {
+		t.Errorf("Expected the escape key by default, got %v", k)
+	}
+	if !s.isSelectKey(KeyEsc) {
+		t.Errorf("Expected the escape key to be a key of the select")
+	}
+
+	s.Keys.ClearSearchKey = &Key{Code: 'x', Display: "x"}
+	if k := s.clearSearchKey(); k.Code != 'x' {
+		t.Errorf("Expected the key set in the Keys, got %v", k)
+	}
+	if s.isSelectKey(KeyEsc) || !s.isSelectKey('x') {
+		t.Errorf("Expected only the key set in the Keys to clear the search")
+	}
+}
+
+type labeledPepper struct {
+	name string
+	heat int
+}
+
+func (p labeledPepper) SelectLabel() string { return p.name }
+
+func (p labeledPepper) SelectDetails() string {
+	return fmt.Sprintf("Heat:\t%d\nName:\t%s", p.heat, p.name)
+}
+
+func TestSelectLabeler(t *testing.T) {
+	peppers := []labeledPepper{{name: "Bell Pepper", heat: 0}, {name: "Habanero", heat: 100000}}
+	s := Select{Items: peppers}
+	err := s.prepareTemplates()
+	if err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	result := string(render(s.Templates.inactive, peppers[1]))
+	if exp := "  Habanero"; result != exp {
+		t.Errorf("Expected inactive item to eq %q, got %q", exp, result)
+	}
+
+	result = string(render(s.Templates.active, peppers[1]))
+	if exp := "\x1b[1m▸\x1b[0m \x1b[4mHabanero\x1b[0m"; result != exp {
+		t.Errorf("Expected active item to eq %q, got %q", exp, result)
+	}
+
+	if _, str, _ := itemString(1, peppers[1], nil); str != "Habanero" {
+		t.Errorf("Expected the label to be returned, got %q", str)
+	}
+
+	details := s.renderDetails(1, peppers[1])
+	exp := [][]byte{[]byte("Heat:\t100000"), []byte("Name:\tHabanero")}
+	if !reflect.DeepEqual(details, exp) {
+		t.Errorf("Expected details %q, got %q", exp, details)
+	}
+
+	s = Select{Items: peppers, Templates: &SelectTemplates{Details: "custom"}}
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+	if details := s.renderDetails(0, peppers[0]); len(details) != 1 || string(details[0]) != "custom" {
+		t.Errorf("Expected the Details template to be used, got %q", details)
+	}
+}
+
+func TestSelectProject(t *testing.T) {
+	peppers := []map[string]int{{"Bell Pepper": 0}, {"Habanero": 100000}}
+	project := func(index int) (string, string) {
+		for name, heat := range peppers[index] {
+			return name, fmt.Sprintf("Heat: %d", heat)
+		}
+		return "", ""
+	}
+
+	s := Select{Items: peppers, Project: project, Size: 5}
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.list = l
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	result := string(render(s.Templates.inactive, s.itemData(1, peppers[1])))
+	if exp := "  Habanero"; result != exp {
+		t.Errorf("Expected inactive item to eq %q, got %q", exp, result)
+	}
+
+	details := s.renderDetails(1, peppers[1])
+	if len(details) != 1 || string(details[0]) != "Heat: 100000" {
+		t.Errorf("Expected the projected details, got %q", details)
+	}
+
+	s.list.Search("haba")
+	if idx := s.cursorIndex(); idx != 1 || s.list.MatchedLen() != 1 {
+		t.Errorf("Expected the search to match the label of item 1, got %d", idx)
+	}
+
+	s = Select{
+		Items:   peppers,
+		Project: project,
+		Stdin:   ioutil.NopCloser(strings.NewReader("Habanero\n")),
+		Stdout:  &bufferCloser{},
+	}
+	idx, value, err := s.Run()
+	if err != nil || idx != 1 || value != "Habanero" {
+		t.Errorf("Expected 1 and Habanero, got %d, %q and %v", idx, value, err)
+	}
+}
+
+func TestSelectSearchPrompt(t *testing.T) {
+	items := []string{"apple", "banana"}
+	s := Select{
+		Items:     items,
+		Size:      5,
+		Searcher:  NewStringSearcher(items),
+		Templates: &SelectTemplates{SearchPrompt: `{{ if matched }}{{ . }}{{ else }}none: {{ . }}{{ end }}`},
+	}
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.list = l
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	s.list.Search("an")
+	if result := string(render(s.Templates.search, "an")); result != "an" {
+		t.Errorf("Expected %q, got %q", "an", result)
+	}
+
+	s.list.Search("z")
+	if result := string(render(s.Templates.search, "z")); result != "none: z" {
+		t.Errorf("Expected %q, got %q", "none: z", result)
+	}
+
+	s = Select{Items: items}
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+	if result := string(render(s.Templates.search, "an")); result != "Search: an" {
+		t.Errorf("Expected %q, got %q", "Search: an", result)
+	}
+}
+
+func TestSelectNoResults(t *testing.T) {
+	s := Select{Items: []string{"apple"}}
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	tcs := map[string]string{
+		"fig": `No results for "fig"`,
+		"":    "No results",
+	}
+	for term, exp := range tcs {
+		if result := string(render(s.Templates.noResults, term)); result != exp {
+			t.Errorf("Expected %q, got %q", exp, result)
+		}
+	}
+}
+
+func TestSelectHelpRange(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e", "f", "g"}
+	s := Select{
+		Items:     items,
+		Size:      3,
+		Searcher:  NewStringSearcher(items),
+		Templates: &SelectTemplates{Help: `{{ .First }}-{{ .Last }} of {{ .Count }}`},
+	}
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.list = l
+	s.setKeys()
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	if result := string(s.renderHelp(true)); result != "1-3 of 7" {
+		t.Errorf("Expected %q, got %q", "1-3 of 7", result)
+	}
+
+	s.list.PageDown()
+	s.list.PageDown()
+	if result := string(s.renderHelp(true)); result != "5-7 of 7" {
+		t.Errorf("Expected %q, got %q", "5-7 of 7", result)
+	}
+
+	s.list.Search("z")
+	if result := string(s.renderHelp(true)); result != "0-0 of 0" {
+		t.Errorf("Expected %q, got %q", "0-0 of 0", result)
+	}
+}
+
+func TestSelectHelpRangeGroups(t *testing.T) {
+	sweet := []string{"Bell Pepper", "Banana Pepper", "Pimento"}
+	hot := []string{"Habanero", "Jalapeno", "Pepper X"}
+	s := Select{
+		Groups:    []SelectGroup{{Name: "Sweet", Items: sweet}, {Name: "Hot", Items: hot}},
+		Size:      4,
+		Searcher:  NewStringSearcher(append(append([]string{}, sweet...), hot...)),
+		Templates: &SelectTemplates{Help: `{{ .First }}-{{ .Last }} of {{ .Count }}`},
+	}
+	l, err := s.newList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.list = l
+	s.setKeys()
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	tcs := []struct {
+		name   string
+		move   func()
+		search string
+		exp    string
+	}{
+		{name: "first page", exp: "1-3 of 6"},
+		{name: "next page", move: s.list.PageDown, exp: "4-6 of 6"},
+		// the header of the hot peppers fills the last line of the page.
+		{name: "search", search: "pepper", exp: "1-2 of 3"},
+	}
+
+	for _, tc := range tcs {
+		if tc.move != nil {
+			tc.move()
+		}
+		if tc.search != "" {
+			s.list.Search(tc.search)
+		}
+		if result := string(s.renderHelp(true)); result != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.exp, result)
+		}
+	}
+}
+
+func TestSelectFitSize(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	tcs := []struct {
+		scenario string
+		s        Select
+		rows     int
+		expected int
+	}{
+		{"with the default templates", Select{Label: "Letter"}, 24, 21},
+		{"without label", Select{HideLabel: true}, 24, 22},
+		{"with details", Select{Label: "Letter", Templates: &SelectTemplates{Details: "one\ntwo"}}, 24, 19},
+		{"in a short terminal", Select{Label: "Letter"}, 3, 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := tc.s
+			s.Items = items
+			s.Size = 5
+			l, err := s.newList()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			s.list = l
+			if err := s.prepareTemplates(); err != nil {
+				t.Fatalf("Unexpected error preparing templates %v", err)
+			}
+
+			if size := s.fitSize(tc.rows); size != tc.expected {
+				t.Errorf("Expected %d items, got %d", tc.expected, size)
+			}
+		})
+	}
+}
+
+func TestSelectShortcuts(t *testing.T) {
+	errEdit := errors.New("edit")
+
+	tcs := []struct {
+		name   string
+		items  []string
+		keys   []string
+		setup  func(s *Select)
+		exp    int
+		expErr error
+	}{
+		{name: "visible item", items: []string{"a", "b", "c", "d", "e"}, keys: []string{"3"}, exp: 2},
+		{name: "scrolled list", items: []string{"a", "b", "c", "d", "e"}, keys: []string{"\x1b[B", "\x1b[B", "\x1b[B", "1"},
+			exp: 1},
+		{name: "no item", items: []string{"a", "b"}, keys: []string{"9", "\r"}, exp: 0},
+		{name: "search mode", items: []string{"a", "b3", "c"}, keys: []string{"3", "\r"}, exp: 1,
+			setup: func(s *Select) {
+				s.Searcher = NewStringSearcher(s.Items.([]string))
+				s.StartInSearchMode = true
+			}},
+		{name: "type-ahead", items: []string{"x", "a2", "a1"}, keys: []string{"a", "1", "\r"}, exp: 2,
+			setup: func(s *Select) { s.TypeAhead = true }},
+		{name: "extra key", items: []string{"a", "b"}, keys: []string{"1"}, exp: 0, expErr: errEdit,
+			setup: func(s *Select) {
+				s.ExtraKeys = map[rune]func(int) error{'1': func(int) error { return errEdit }}
+			}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{Items: tc.items, Size: 3, Shortcuts: true, Terminal: term, Stdin: term, Stdout: term}
+			if tc.setup != nil {
+				tc.setup(&s)
+			}
+
+			index, _, err := s.Run()
+			if err != tc.expErr {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+			if index != tc.exp {
+				t.Errorf("Expected index %d, got %d", tc.exp, index)
+			}
+		})
+	}
+
+	t.Run("template", func(t *testing.T) {
+		term := NewTestTerminal("\r")
+		s := Select{
+			Items:     []string{"a", "b", "c", "d", "e"},
+			Size:      3,
+			Shortcuts: true,
+			Templates: &SelectTemplates{Shortcut: "[{{ . }}]"},
+			Terminal:  term,
+			Stdin:     term,
+			Stdout:    term,
+		}
+
+		if _, _, err := s.Run(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		out := term.Output()
+		if !strings.Contains(out, "[1] ") || !strings.Contains(out, "[3] ") || strings.Contains(out, "[4]") {
+			t.Errorf("Expected the visible items to be numbered, got %q", out)
+		}
+	})
+}
+
+func TestSelectAutoSelectSingle(t *testing.T) {
+	tcs := []struct {
+		name         string
+		items        []string
+		hideSelected bool
+		expErr       error
+		expOut       string
+	}{
+		{name: "single item", items: []string{"Bell Pepper"}, expOut: "Bell Pepper\n"},
+		{name: "hidden selection", items: []string{"Bell Pepper"}, hideSelected: true},
+		{name: "no item", items: []string{}, expErr: ErrEmptyList},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			// the terminal has no keys to read, so reading the input would end the select with ErrEOF.
+			term := NewTestTerminal()
+			var out bufferCloser
+			s := Select{
+				Items:            tc.items,
+				AutoSelectSingle: true,
+				HideSelected:     tc.hideSelected,
+				Templates:        &SelectTemplates{Selected: "{{ . }}"},
+				Terminal:         term,
+				Stdin:            term,
+				Stdout:           &out,
+			}
+
+			index, value, err := s.Run()
+			if err != tc.expErr {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+			if err == nil && (index != 0 || value != tc.items[0]) {
+				t.Errorf("Expected %d %q, got %d %q", 0, tc.items[0], index, value)
+			}
+			if out.String() != tc.expOut {
+				t.Errorf("Expected output %q, got %q", tc.expOut, out.String())
+			}
+		})
+	}
+}
+
+func TestSelectEmptyList(t *testing.T) {
+	tcs := map[string]interface{}{
+		"empty slice": []string{},
+		"nil":         nil,
+	}
+
+	for name, items := range tcs {
+		t.Run(name, func(t *testing.T) {
+			var out bufferCloser
+			s := Select{Items: items, Stdin: ioutil.NopCloser(strings.NewReader("")), Stdout: &out}
+
+			if _, _, err := s.Run(); err != ErrEmptyList {
+				t.Errorf("Expected %v, got %v", ErrEmptyList, err)
+			}
+			if _, _, err := s.RunCursorAt(0, 0); err != ErrEmptyList {
+				t.Errorf("Expected %v, got %v", ErrEmptyList, err)
+			}
+			if out.Len() != 0 {
+				t.Errorf("Expected no output, got %q", out.String())
+			}
+		})
+	}
+
+	t.Run("empty groups", func(t *testing.T) {
+		s := Select{Groups: []SelectGroup{{Name: "Peppers", Items: []string{}}}}
+		if _, _, err := s.Run(); err != ErrEmptyList {
+			t.Errorf("Expected %v, got %v", ErrEmptyList, err)
+		}
+	})
+}
+
+func TestSelectPlainSuccess(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(false)
+
+	s := Select{
+		Items:        []string{"Bell Pepper"},
+		PlainSuccess: true,
+		Templates:    &SelectTemplates{Selected: `{{ . | green }}`},
+	}
+	if err := s.prepareTemplates(); err != nil {
+		t.Fatalf("Unexpected error preparing templates %v", err)
+	}
+
+	var out bytes.Buffer
+	s.renderSelected(screenbuf.New(&out, true), []interface{}{"Bell Pepper"})
+	if !strings.Contains(out.String(), "Bell Pepper") || strings.Contains(out.String(), "\x1b[32m") {
+		t.Errorf("Expected the selected item without styles, got %q", out.String())
+	}
+}
+
+type detailedPepper struct {
+	Name  string
+	Notes []string
+}
+
+func TestSelectDetailsHeight(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	peppers := []detailedPepper{
+		{Name: "Bell Pepper", Notes: []string{"sweet", "mild", "crunchy"}},
+		{Name: "Habanero", Notes: []string{"hot"}},
+		{Name: "Jalapeno", Notes: []string{"spicy", "smoky"}},
+	}
+
+	tcs := []struct {
+		name string
+		keys []string
+		exp  []string
+	}{
+		{name: "three lines", keys: []string{}, exp: []string{"sweet", "mild", "crunchy"}},
+		{name: "shrinking to one line", keys: []string{"\x1b[B"}, exp: []string{"hot"}},
+		{name: "growing to two lines", keys: []string{"\x1b[B", "\x1b[B"}, exp: []string{"spicy", "smoky"}},
+		{name: "shrinking back", keys: []string{"\x1b[B", "\x1b[B", "\x1b[A"}, exp: []string{"hot"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			s := Select{
+				Label:    "Pepper",
+				Items:    peppers,
+				HideHelp: true,
+				Templates: &SelectTemplates{
+					Active:   "> {{ .Name }}",
+					Inactive: "  {{ .Name }}",
+					Details:  `{{ range .Notes }}{{ . }}{{ "\n" }}{{ end }}`,
+				},
+				Terminal: term,
+				Stdin:    ioutil.NopCloser(in),
+				Stdout:   term,
+			}
+			s.Run()
+
+			if len(screen) < 4 {
+				t.Fatalf("Expected the label and the items, got %q", screen)
+			}
+			if details := screen[4:]; !reflect.DeepEqual(details, tc.exp) {
+				t.Errorf("Expected details %q, got %q in %q", tc.exp, details, screen)
+			}
+		})
+	}
+}
+
+func TestSelectShowCursor(t *testing.T) {
+	tcs := []struct {
+		name   string
+		keys   []string
+		show   bool
+		err    error
+		hidden bool
+	}{
+		{name: "selected", keys: []string{"\r"}, hidden: true},
+		{name: "interrupted", keys: []string{"\x03"}, err: ErrInterrupt, hidden: true},
+		{name: "input closed", err: ErrEOF, hidden: true},
+		{name: "shown", keys: []string{"\r"}, show: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:      "Pepper",
+				Items:      []string{"Bell Pepper", "Habanero"},
+				ShowCursor: tc.show,
+				Terminal:   term,
+				Stdin:      term,
+				Stdout:     term,
+			}
+
+			if _, _, err := s.Run(); err != tc.err {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+
+			out := term.Output()
+			if !tc.hidden {
+				if strings.Contains(out, hideCursor) || strings.Contains(out, showCursor) {
+					t.Errorf("Expected the cursor to be left visible, got %q", out)
+				}
+				return
+			}
+			if !strings.HasPrefix(out, hideCursor) {
+				t.Errorf("Expected the cursor to be hidden before the first frame, got %q", out)
+			}
+			if strings.LastIndex(out, showCursor) < strings.LastIndex(out, hideCursor) {
+				t.Errorf("Expected the cursor to be shown again once the select ended, got %q", out)
+			}
+		})
+	}
+}
+
+func TestSelectOnSelect(t *testing.T) {
+	items := []string{"Bell Pepper", "Habanero", "Jalapeno"}
+
+	var selected []int
+	term := NewTestTerminal("\x1b[B", "\r")
+	s := Select{
+		Label:    "Pepper",
+		Items:    items,
+		OnSelect: func(index int) { selected = append(selected, index) },
+		Terminal: term,
+		Stdin:    term,
+		Stdout:   term,
+	}
+
+	if _, _, err := s.Run(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(selected, []int{1}) {
+		t.Errorf("Expected OnSelect to be called with 1, got %v", selected)
+	}
+
+	selected = nil
+	term = NewTestTerminal("\x03")
+	s.Terminal, s.Stdin, s.Stdout = term, term, term
+	if _, _, err := s.Run(); err != ErrInterrupt {
+		t.Fatalf("Expected error %v, got %v", ErrInterrupt, err)
+	}
+	if len(selected) != 0 {
+		t.Errorf("Expected OnSelect not to be called when interrupted, got %v", selected)
+	}
+
+	term = NewTestTerminal(" ", "\x1b[B", "\x1b[B", " ", "\r")
+	m := MultiSelect{Select: Select{
+		Label:    "Peppers",
+		Items:    items,
+		OnSelect: func(index int) { selected = append(selected, index) },
+		Terminal: term,
+		Stdin:    term,
+		Stdout:   term,
+	}}
+	if _, _, err := m.Run(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(selected, []int{0, 2}) {
+		t.Errorf("Expected OnSelect to be called with 0 and 2, got %v", selected)
+	}
+}
+
+func TestSelectRunResult(t *testing.T) {
+	items := []string{"Bell Pepper", "Habanero", "Jalapeno"}
+
+	tcs := []struct {
+		name      string
+		keys      []string
+		search    bool
+		shortcuts bool
+		expIdx    int
+		expMethod SelectMethod
+	}{
+		{name: "navigate", keys: []string{"\x1b[B", "\r"}, expIdx: 1, expMethod: NavigateEnter},
+		{name: "search", keys: []string{"Jal", "\r"}, search: true, expIdx: 2, expMethod: SearchEnter},
+		{name: "search then navigate", keys: []string{"/", "Jal", "/", "\r"}, expIdx: 2, expMethod: NavigateEnter},
+		{name: "shortcut", keys: []string{"2"}, shortcuts: true, expIdx: 1, expMethod: Shortcut},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:             "Pepper",
+				Items:             items,
+				Searcher:          NewStringSearcher(items),
+				StartInSearchMode: tc.search,
+				Shortcuts:         tc.shortcuts,
+				Terminal:          term,
+				Stdin:             term,
+				Stdout:            term,
+			}
+
+			res, err := s.RunResult()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if res.Index != tc.expIdx || res.Item != items[tc.expIdx] || res.Method != tc.expMethod {
+				t.Errorf("Expected %d %q by %d, got %d %v by %d", tc.expIdx, items[tc.expIdx], tc.expMethod, res.Index, res.Item, res.Method)
+			}
+		})
+	}
+
+	t.Run("piped", func(t *testing.T) {
+		s := Select{
+			Label:  "Pepper",
+			Items:  items,
+			Stdin:  ioutil.NopCloser(strings.NewReader("Habanero\n")),
+			Stdout: &bufferCloser{},
+		}
+
+		res, err := s.RunResult()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if res.Index != 1 || res.Method != Unattended {
+			t.Errorf("Expected 1 by %d, got %d by %d", Unattended, res.Index, res.Method)
+		}
+	})
+}
+
+func TestSelectPanic(t *testing.T) {
+	items := []string{"Bell Pepper", "Habanero"}
+
+	tcs := []struct {
+		name   string
+		keys   []string
+		search bool
+		extra  bool
+	}{
+		{name: "searcher", keys: []string{"/", "H"}, search: true},
+		{name: "extra key", keys: []string{"e"}, extra: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:    "Pepper",
+				Items:    items,
+				Terminal: term,
+				Stdin:    term,
+				Stdout:   term,
+			}
+			if tc.search {
+				s.Searcher = func(input string, index int) bool { panic("boom") }
+			}
+			if tc.extra {
+				s.ExtraKeys = map[rune]func(int) error{'e': func(int) error { panic("boom") }}
+			}
+
+			func() {
+				defer func() {
+					if v := recover(); v != "boom" {
+						t.Errorf("Expected the panic to be raised again, got %v", v)
+					}
+				}()
+				s.Run()
+			}()
+
+			if term.IsRaw() {
+				t.Errorf("Expected the terminal to be restored")
+			}
+			if out := term.Output(); !strings.Contains(out, showCursor) {
+				t.Errorf("Expected the cursor to be shown, got %q", out)
+			}
+		})
+	}
+}
+
+func TestSelectBell(t *testing.T) {
+	tcs := []struct {
+		name     string
+		keys     []string
+		wrap     bool
+		bell     bool
+		expected int
+	}{
+		{name: "top", keys: []string{"\x1b[A", "\r"}, bell: true, expected: 1},
+		{name: "bottom", keys: []string{"\x1b[B", "\x1b[B", "\x1b[B", "\x1b[B", "\r"}, bell: true, expected: 2},
+		{name: "moved", keys: []string{"\x1b[B", "\x1b[A", "\r"}, bell: true, expected: 0},
+		{name: "wrapped", keys: []string{"\x1b[A", "\r"}, wrap: true, bell: true, expected: 0},
+		{name: "disabled", keys: []string{"\x1b[A", "\r"}, expected: 0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:          "Pepper",
+				Items:          []string{"Bell Pepper", "Habanero", "Jalapeno"},
+				WrapNavigation: tc.wrap,
+				Bell:           tc.bell,
+				Terminal:       term,
+				Stdin:          term,
+				Stdout:         term,
+			}
+
+			if _, _, err := s.Run(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if n := strings.Count(term.Output(), bell); n != tc.expected {
+				t.Errorf("Expected the bell rung %d times, got %d", tc.expected, n)
+			}
+		})
+	}
+}
+
+func TestSelectRunAndConfirm(t *testing.T) {
+	tcs := []struct {
+		name   string
+		keys   []string
+		expIdx int
+		expYes bool
+		expErr error
+	}{
+		{name: "confirmed", keys: []string{"\x1b[B", "\r", "y", "\r"}, expIdx: 1, expYes: true},
+		{name: "declined", keys: []string{"\r", "n", "\r"}},
+		{name: "interrupted select", keys: []string{"\x03"}, expErr: ErrAbort},
+		{name: "interrupted confirm", keys: []string{"\x1b[B", "\r", "\x03"}, expIdx: 1, expErr: ErrAbort},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:    "Delete",
+				Items:    []string{"Bell Pepper", "Habanero"},
+				Terminal: term,
+				Stdin:    term,
+				Stdout:   term,
+			}
+
+			idx, item, yes, err := s.RunAndConfirm("Really delete")
+			if err != tc.expErr {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+			if yes != tc.expYes {
+				t.Errorf("Expected the answer %v, got %v", tc.expYes, yes)
+			}
+			if tc.expErr == nil && (idx != tc.expIdx || item != s.Items.([]string)[tc.expIdx]) {
+				t.Errorf("Expected the item %d, got %d %v", tc.expIdx, idx, item)
+			}
+			if tc.expErr == nil && !strings.Contains(term.Output(), "Really delete") {
+				t.Errorf("Expected the confirm question, got %q", term.Output())
+			}
+		})
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	tcs := []struct {
+		name                           string
+		max                            int
+		help                           bool
+		label, items, details          int
+		expHelp                        bool
+		expLabel, expItems, expDetails int
+	}{
+		{name: "fitting", max: 10, help: true, label: 1, items: 5, details: 3,
+			expHelp: true, expLabel: 1, expItems: 5, expDetails: 3},
+		{name: "details truncated", max: 8, help: true, label: 1, items: 5, details: 3,
+			expHelp: true, expLabel: 1, expItems: 5, expDetails: 1},
+		{name: "help dropped", max: 6, help: true, label: 1, items: 5, details: 3,
+			expLabel: 1, expItems: 5},
+		{name: "items reduced", max: 4, help: true, label: 1, items: 5, details: 3,
+			expLabel: 1, expItems: 3},
+		{name: "label truncated", max: 2, help: true, label: 3, items: 5, details: 3,
+			expLabel: 1, expItems: 1},
+		{name: "single item", max: 1, label: 1, items: 5,
+			expItems: 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			help, label, items, details := fitLines(tc.max, tc.help, tc.label, tc.items, 1, tc.details)
+			if help != tc.expHelp || label != tc.expLabel || items != tc.expItems || details != tc.expDetails {
+				t.Errorf("Expected %v %d %d %d, got %v %d %d %d", tc.expHelp, tc.expLabel, tc.expItems, tc.expDetails,
+					help, label, items, details)
+			}
+		})
+	}
+}
+
+func TestSelectMaxLines(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	peppers := []detailedPepper{
+		{Name: "Bell Pepper", Notes: []string{"sweet", "mild", "crunchy"}},
+		{Name: "Habanero"},
+		{Name: "Jalapeno"},
+		{Name: "Poblano"},
+		{Name: "Serrano"},
+		{Name: "Cayenne"},
+	}
+
+	tcs := []struct {
+		name     string
+		maxLines int
+		keys     []string
+		exp      []string
+	}{
+		{name: "details truncated", maxLines: 8, exp: []string{"help", "? Pepper:", "  > Bell Pepper", "    Habanero",
+			"    Jalapeno", "    Poblano", "↓   Serrano", "sweet"}},
+		{name: "items reduced", maxLines: 3, exp: []string{"? Pepper:", "  > Bell Pepper", "↓   Habanero"}},
+		{name: "details shrinking", maxLines: 3, keys: []string{"\x1b[B"}, exp: []string{"? Pepper:",
+			"    Bell Pepper", "↓ > Habanero"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			s := Select{
+				Label:    "Pepper",
+				Items:    peppers,
+				MaxLines: tc.maxLines,
+				Templates: &SelectTemplates{
+					Help:     "help",
+					Active:   "> {{ .Name }}",
+					Inactive: "  {{ .Name }}",
+					Details:  `{{ range .Notes }}{{ . }}{{ "\n" }}{{ end }}`,
+				},
+				Terminal: term,
+				Stdin:    ioutil.NopCloser(in),
+				Stdout:   term,
+			}
+			s.Run()
+
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		})
+	}
+}
+
+func TestSelectSearchDebounceEcho(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	term := NewTestTerminal("/", "H", "a", "b")
+	var screen []string
+	in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+	searched := 0
+	items := []string{"Bell Pepper", "Habanero", "Jalapeno"}
+	s := Select{
+		Label: "Pepper",
+		Items: items,
+		Searcher: func(input string, index int) bool {
+			searched++
+			return strings.Contains(items[index], input)
+		},
+		SearchDebounce: time.Hour,
+		Templates: &SelectTemplates{
+			Active:   "> {{ . }}",
+			Inactive: "  {{ . }}",
+		},
+		Terminal: term,
+		Stdin:    ioutil.NopCloser(in),
+		Stdout:   term,
+	}
+	s.Run()
+
+	exp := []string{"Search: Hab█", "? Pepper:", "  > Bell Pepper", "    Habanero", "    Jalapeno"}
+	if !reflect.DeepEqual(screen, exp) {
+		t.Errorf("Expected %q, got %q", exp, screen)
+	}
+	if searched != 0 {
+		t.Errorf("Expected the search to be deferred, got %d items searched", searched)
+	}
+}
+
+func TestSelectFooter(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	footer := func(index int) string {
+		if index == list.NotFound {
+			return "no pepper"
+		}
+		return fmt.Sprintf("pepper %d of 3\nignored", index+1)
+	}
+
+	tcs := []struct {
+		name     string
+		maxLines int
+		keys     []string
+		exp      []string
+	}{
+		{name: "first", exp: []string{"help", "? Pepper:", "  > Bell Pepper", "↓   Habanero", "pepper 1 of 3"}},
+		{name: "paged", keys: []string{"\x1b[B", "\x1b[B"}, exp: []string{"help", "? Pepper:", "↑   Habanero",
+			"  > Jalapeno", "pepper 3 of 3"}},
+		{name: "no match", keys: []string{"/", "z"}, exp: []string{"Search: z█", "? Pepper:", "", "No results for \"z\"",
+			"no pepper"}},
+		{name: "max lines", maxLines: 3, exp: []string{"? Pepper:", "  > Bell Pepper", "pepper 1 of 3"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			items := []string{"Bell Pepper", "Habanero", "Jalapeno"}
+			s := Select{
+				Label:    "Pepper",
+				Items:    items,
+				Size:     2,
+				Searcher: NewStringSearcher(items),
+				MaxLines: tc.maxLines,
+				Footer:   footer,
+				Templates: &SelectTemplates{
+					Help:     "help",
+					Active:   "> {{ . }}",
+					Inactive: "  {{ . }}",
+				},
+				Terminal: term,
+				Stdin:    ioutil.NopCloser(in),
+				Stdout:   term,
+			}
+			s.Run()
+
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		})
+	}
+}
+
+func TestSelectTranscript(t *testing.T) {
+	tcs := []struct {
+		name   string
+		keys   []string
+		checks bool
+		exp    string
+	}{
+		{name: "selected", keys: []string{"\x1b[B", "\r"}, exp: "Pepper: Habanero\n"},
+		{name: "multiple selected", keys: []string{" ", "\x1b[B", " ", "\r"}, checks: true,
+			exp: "Pepper: Bell Pepper, Habanero\n"},
+		{name: "interrupted", keys: []string{"\x1b[B", "\x03"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var transcript bytes.Buffer
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:            "Pepper",
+				Items:            []string{"Bell Pepper", "Habanero", "Jalapeno"},
+				TranscriptWriter: &transcript,
+				Terminal:         term,
+				Stdin:            term,
+				Stdout:           term,
+			}
+
+			if tc.checks {
+				ms := MultiSelect{Select: s}
+				ms.Run()
+			} else {
+				s.Run()
+			}
+			if transcript.String() != tc.exp {
+				t.Errorf("Expected %q, got %q", tc.exp, transcript.String())
+			}
+		})
+	}
+}
+
+func TestSelectRedraw(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	term := NewTestTerminal("\x1b[B", "\x0c")
+	var screen []string
+	in := &pausedReader{TestTerminal: term, done: func() {
+		out := term.Output()
+		screen = emulate(out[strings.LastIndex(out, "\x1b[2J\x1b[H"):])
+	}}
+
+	s := Select{
+		Label:     "Pepper",
+		Items:     []string{"Bell Pepper", "Habanero"},
+		HideHelp:  true,
+		Templates: &SelectTemplates{Active: "> {{ . }}", Inactive: "  {{ . }}"},
+		Terminal:  term,
+		Stdin:     ioutil.NopCloser(in),
+		Stdout:    term,
+	}
+	s.Run()
+
+	exp := []string{"? Pepper:", "    Bell Pepper", "  > Habanero"}
+	if !reflect.DeepEqual(screen, exp) {
+		t.Errorf("Expected the select drawn again from scratch %q, got %q", exp, screen)
+	}
+}
+
+func TestSelectWithAdd(t *testing.T) {
+	validate := func(input string) error {
+		if len(input) < 3 {
+			return errors.New("too short")
+		}
+		return nil
+	}
+
+	tcs := []struct {
+		name  string
+		keys  []string
+		index int
+		value string
+		err   error
+		items []string
+	}{
+		{name: "existing", keys: []string{"\x1b[B", "\r"}, index: 1, value: "Emacs",
+			items: []string{"Vim", "Emacs"}},
+		{name: "added", keys: []string{"\x1b[A", "\r", "Nano", "\r"}, index: SelectedAdd, value: "Nano",
+			items: []string{"Vim", "Emacs", "Nano"}},
+		{name: "added after invalid", keys: []string{"\x1b[A", "\r", "Ed", "\r", "\x7f\x7f", "Joe", "\r"}, index: SelectedAdd,
+			value: "Joe", items: []string{"Vim", "Emacs", "Joe"}},
+		{name: "interrupted list", keys: []string{"\x1b[B", "\x03"}, index: SelectedAdd, err: ErrInterrupt,
+			items: []string{"Vim", "Emacs"}},
+		{name: "interrupted prompt", keys: []string{"\x1b[A", "\r", "Na", "\x03"}, index: SelectedAdd, err: ErrInterrupt,
+			items: []string{"Vim", "Emacs"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			sa := SelectWithAdd{
+				Label:       "Editor",
+				Items:       []string{"Vim", "Emacs"},
+				AddLabel:    "Add your own",
+				Validate:    validate,
+				AppendAdded: true,
+				Terminal:    term,
+				Stdin:       term,
+				Stdout:      term,
+			}
+
+			index, value, err := sa.Run()
+			if index != tc.index || value != tc.value || err != tc.err {
+				t.Errorf("Expected %d, %q and %v, got %d, %q and %v", tc.index, tc.value, tc.err, index, value, err)
+			}
+			if !reflect.DeepEqual(sa.Items, tc.items) {
+				t.Errorf("Expected the items %q, got %q", tc.items, sa.Items)
+			}
+			if term.IsRaw() {
+				t.Error("Expected the terminal restored")
+			}
+		})
+	}
+}
+
+func TestSelectPrepareTemplates(t *testing.T) {
+	s := Select{Label: "Pepper", Items: []string{"Bell Pepper"}, Templates: &SelectTemplates{Details: "{{ . }}"}}
+	if err := s.PrepareTemplates(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	compiled := s.Templates.label
+
+	if err := s.prepareTemplates(); err != nil || s.Templates.label != compiled {
+		t.Errorf("Expected the templates kept compiled, got %v", err)
+	}
+
+	if err := s.PrepareTemplates(); err != nil || s.Templates.label == compiled {
+		t.Errorf("Expected PrepareTemplates to compile the templates again, got %v", err)
+	}
+	compiled = s.Templates.label
+
+	s.Templates.Details = ""
+	if err := s.PrepareTemplates(); err != nil || s.Templates.label == compiled || s.Templates.details != nil {
+		t.Errorf("Expected the templates compiled again without details, got %v", err)
+	}
+
+	s.Templates.FuncMap = template.FuncMap{"bold": FuncMap["bold"]}
+	if err := s.PrepareTemplates(); err == nil {
+		t.Error("Expected the error of the templates using functions missing from the FuncMap")
+	}
+}
+
+func TestSelectInitialSearch(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	items := []string{"Bell", "Jalapeno", "Bell Pepper", "Habanero"}
+	tcs := []struct {
+		name   string
+		keys   []string
+		search bool
+		exp    []string
+	}{
+		{name: "filtered", exp: []string{"Search: bell", "? Pepper:", "  ▸ Bell", "    Bell Pepper"}},
+		{name: "cleared", keys: []string{"\x1b"},
+			exp: []string{"? Pepper:", "  ▸ Bell", "    Jalapeno", "    Bell Pepper", "    Habanero"}},
+		{name: "edited", keys: []string{"\x7f\x7f\x7f\x7f", "ha"}, search: true,
+			exp: []string{"Search: ha█", "? Pepper:", "  ▸ Habanero"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			s := Select{
+				Label:             "Pepper",
+				Items:             items,
+				Searcher:          NewStringSearcher(items),
+				InitialSearch:     "bell",
+				StartInSearchMode: tc.search,
+				HideHelp:          true,
+				Terminal:          term,
+				Stdin:             ioutil.NopCloser(in),
+				Stdout:            term,
+			}
+			s.Run()
+
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		})
+	}
+}
+
+func TestSelectAutoSizeRows(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	var items []string
+	for i := 1; i <= 20; i++ {
+		items = append(items, fmt.Sprintf("Pepper %d", i))
+	}
+
+	tcs := []struct {
+		name string
+		rows int
+		keys []string
+		exp  []string
+	}{
+		{name: "clamped", rows: 6, exp: []string{"? Pepper:", "  ▸ Pepper 1", "    Pepper 2", "↓   Pepper 3"}},
+		{name: "scrolled", rows: 6, keys: []string{"\x1b[B", "\x1b[B", "\x1b[B"},
+			exp: []string{"? Pepper:", "↑   Pepper 2", "    Pepper 3", "↓ ▸ Pepper 4"}},
+		// the Size is ignored, so the taller terminal shows more items than it.
+		{name: "taller", rows: 15, exp: []string{"? Pepper:", "  ▸ Pepper 1", "    Pepper 2", "    Pepper 3",
+			"    Pepper 4", "    Pepper 5", "    Pepper 6", "    Pepper 7", "    Pepper 8", "    Pepper 9",
+			"    Pepper 10", "    Pepper 11", "↓   Pepper 12"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			term.Rows = tc.rows
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			s := Select{
+				Label:    "Pepper",
+				Items:    items,
+				Size:     10,
+				AutoSize: true,
+				HideHelp: true,
+				Terminal: term,
+				Stdin:    ioutil.NopCloser(in),
+				Stdout:   term,
+			}
+			s.Run()
+
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		})
+	}
+}
+
+func TestSelectMouseRun(t *testing.T) {
+	// the terminal replies that its cursor is on row 10 below the frame of 6 lines, so the items are on the rows 5
+	// to 9.
+	const reply = "\x1b[10;1R"
+	click := func(row int) string { return fmt.Sprintf("\x1b[<0;3;%dM", row) }
+	release := func(row int) string { return fmt.Sprintf("\x1b[<0;3;%dm", row) }
+	wheelDown, wheelUp := "\x1b[<65;3;5M", "\x1b[<64;3;5M"
+
+	tcs := []struct {
+		name   string
+		keys   []string
+		index  int
+		method SelectMethod
+	}{
+		{name: "wheel down", keys: []string{wheelDown, wheelDown, "\r"}, index: 2, method: NavigateEnter},
+		{name: "wheel up", keys: []string{wheelDown, wheelDown, wheelUp, "\r"}, index: 1, method: NavigateEnter},
+		{name: "click", keys: []string{reply, click(9), release(9), "\r"}, index: 4, method: NavigateEnter},
+		{name: "click on the label", keys: []string{reply, click(4), "\r"}, index: 0, method: NavigateEnter},
+		{name: "double click", keys: []string{reply, click(7), release(7), click(7)}, index: 2, method: Mouse},
+		// the rows of the frame are unknown until the terminal replied.
+		{name: "click without reply", keys: []string{click(9), "\r"}, index: 0, method: NavigateEnter},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			term := NewTestTerminal(tc.keys...)
+			s := Select{
+				Label:       "Pepper",
+				Items:       []string{"Bell", "Jalapeno", "Habanero", "Serrano", "Poblano"},
+				HideHelp:    true,
+				EnableMouse: true,
+				Terminal:    term,
+				Stdin:       term,
+				Stdout:      term,
+			}
+
+			res, err := s.RunResult()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if res.Index != tc.index || res.Method != tc.method {
+				t.Errorf("Expected index %d by %v, got %d by %v", tc.index, tc.method, res.Index, res.Method)
+			}
+			if out := term.Output(); !strings.Contains(out, enableMouse) || !strings.Contains(out, disableMouse) {
+				t.Errorf("Expected the mouse reporting turned on and off, got %q", out)
+			}
+		})
+	}
+}
+
+func TestSelectSearchDebounceRun(t *testing.T) {
+	defer DisableColors(colorsDisabled)
+	DisableColors(true)
+
+	items := []string{"Bell", "Jalapeno", "Habanero"}
+	tcs := []struct {
+		name     string
+		debounce time.Duration
+		searched []string
+		exp      []string
+	}{
+		{name: "each key", searched: []string{"h", "ha", "hab"},
+			exp: []string{"Search: hab█", "? Pepper:", "  ▸ Habanero"}},
+		{name: "debounced", debounce: 50 * time.Millisecond, searched: []string{"hab"},
+			exp: []string{"Search: hab█", "? Pepper:", "  ▸ Habanero"}},
+		// the typed term is echoed while its search is pending.
+		{name: "pending", debounce: time.Hour,
+			exp: []string{"Search: hab█", "? Pepper:", "  ▸ Bell", "    Jalapeno", "    Habanero"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var searched []string
+			search := NewStringSearcher(items)
+
+			term := NewTestTerminal("h", "a", "b")
+			var screen []string
+			in := &pausedReader{TestTerminal: term, done: func() { screen = emulate(term.Output()) }}
+
+			s := Select{
+				Label: "Pepper",
+				Items: items,
+				Searcher: func(input string, index int) bool {
+					mu.Lock()
+					defer mu.Unlock()
+					if index == 0 {
+						searched = append(searched, input)
+					}
+					return search(input, index)
+				},
+				SearchDebounce:    tc.debounce,
+				StartInSearchMode: true,
+				HideHelp:          true,
+				Terminal:          term,
+				Stdin:             ioutil.NopCloser(in),
+				Stdout:            term,
+			}
+			s.Run()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(searched, tc.searched) {
+				t.Errorf("Expected the searches %q, got %q", tc.searched, searched)
+			}
+			if !reflect.DeepEqual(screen, tc.exp) {
+				t.Errorf("Expected %q, got %q", tc.exp, screen)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package promptui
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/logrhythm/promptui/screenbuf"
+)
+
+// DotsFrames are the frames of a spinner made of braille dots. They are the default frames of NewSpinner.
+var DotsFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// ASCIIFrames are the frames of a spinner made of ASCII characters only, for the terminals and fonts lacking the
+// braille glyphs.
+var ASCIIFrames = []string{"|", "/", "-", "\\"}
+
+// DefaultSpinnerInterval is the time each frame of a spinner is displayed when its Interval is not set.
+const DefaultSpinnerInterval = 100 * time.Millisecond
+
+// Spinner displays an animated indicator followed by a message on a single line while some work is done between
+// prompts. The line is redrawn in place on its own goroutine, between the calls to Start and Stop.
+//
+// When the output is not a terminal, the frames are not animated and the message is written once on its own line
+// each time it changes.
+type Spinner struct {
+	// Interval is the time each frame is displayed. Defaults to DefaultSpinnerInterval.
+	Interval time.Duration
+
+	// ForceColors keeps the animation and the cursor movements when the output is not a terminal.
+	ForceColors bool
+
+	w      io.Writer
+	frames []string
+
+	mu      sync.Mutex
+	sb      *screenbuf.ScreenBuf
+	plain   bool
+	message string
+	frame   int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSpinner creates a spinner writing to w and cycling through the given frames. DotsFrames are used when no
+// frames are given.
+func NewSpinner(w io.Writer, frames []string) *Spinner {
+	if len(frames) == 0 {
+		frames = DotsFrames
+	}
+	return &Spinner{w: w, frames: frames}
+}
+
+// SetMessage sets the message displayed after the frames. It can be called while the spinner is running.
+func (s *Spinner) SetMessage(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.message = message
+	if s.sb != nil {
+		s.draw()
+	}
+}
+
+// Start displays the spinner and animates it until Stop is called. Calling Start on a running spinner does
+// nothing.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sb != nil {
+		return
+	}
+
+	s.plain = !s.ForceColors && !isTerminal(s.w)
+	s.sb = screenbuf.New(s.w, true)
+	s.sb.Plain = s.plain
+	s.frame = 0
+
+	if !s.plain {
+		s.w.Write([]byte(hideCursor))
+	}
+	s.draw()
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultSpinnerInterval
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.animate(interval, s.stop, s.done)
+}
+
+// Stop stops the animation and clears the line of the spinner, leaving the cursor at its start. Calling Stop on
+// a spinner that is not running does nothing.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	if s.sb == nil {
+		s.mu.Unlock()
+		return
+	}
+	close(s.stop)
+	s.mu.Unlock()
+
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clearScreen(s.sb)
+	if !s.plain {
+		s.w.Write([]byte(showCursor))
+	}
+	s.sb = nil
+}
+
+func (s *Spinner) animate(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame = (s.frame + 1) % len(s.frames)
+			s.draw()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// draw writes the current frame and message. It must be called with the lock held.
+func (s *Spinner) draw() {
+	line := s.frames[s.frame] + " " + s.message
+	if s.plain {
+		if s.message == "" {
+			return
+		}
+		line = s.message
+	}
+
+	s.sb.WriteString(line)
+	s.sb.Flush()
+}
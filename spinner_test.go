@@ -0,0 +1,63 @@
+package promptui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinner(t *testing.T) {
+	t.Run("animates the frames on a single line", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := NewSpinner(&buf, ASCIIFrames)
+		s.Interval = time.Millisecond
+		s.ForceColors = true
+		s.SetMessage("Loading")
+
+		s.Start()
+		time.Sleep(20 * time.Millisecond)
+		s.Stop()
+
+		out := buf.String()
+		for _, frame := range []string{"| Loading", "/ Loading"} {
+			if !strings.Contains(out, frame) {
+				t.Errorf("Expected frame %q in %q", frame, out)
+			}
+		}
+		if !strings.HasPrefix(out, hideCursor) {
+			t.Errorf("Expected the cursor to be hidden in %q", out)
+		}
+		if !strings.HasSuffix(out, upLine(1)+clearLine+"\r"+showCursor) {
+			t.Errorf("Expected the line to be cleared in %q", out)
+		}
+	})
+
+	t.Run("writes the messages as plain lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := NewSpinner(&buf, nil)
+		s.Interval = time.Millisecond
+		s.SetMessage("Loading")
+
+		s.Start()
+		time.Sleep(5 * time.Millisecond)
+		s.SetMessage("Saving")
+		time.Sleep(5 * time.Millisecond)
+		s.Stop()
+
+		expected := "Loading\nSaving\n"
+		if buf.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, buf.String())
+		}
+	})
+
+	t.Run("ignores repeated calls", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := NewSpinner(&buf, nil)
+		s.Stop()
+		s.Start()
+		s.Start()
+		s.Stop()
+		s.Stop()
+	})
+}
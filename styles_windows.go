@@ -5,17 +5,22 @@ package promptui
 var (
 	// IconInitial is the icon used when starting in prompt mode and the icon next to the label when
 	// starting in select mode.
-	IconInitial = Styler(FGBlue)("?")
+	IconInitial string
 
 	// IconGood is the icon used when a good answer is entered in prompt mode.
-	IconGood = Styler(FGGreen)("v")
+	IconGood string
 
 	// IconWarn is the icon used when a good, but potentially invalid answer is entered in prompt mode.
-	IconWarn = Styler(FGYellow)("!")
+	IconWarn string
 
 	// IconBad is the icon used when a bad answer is entered in prompt mode.
-	IconBad = Styler(FGRed)("x")
+	IconBad string
 
 	// IconSelect is the icon used to identify the currently selected item in select mode.
-	IconSelect = Styler(FGBold)(">")
+	IconSelect string
 )
+
+// setIcons sets the icons to their default value, styled unless the colors are disabled.
+func setIcons() {
+	useIcons(ASCIIIcons())
+}
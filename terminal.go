@@ -0,0 +1,168 @@
+package promptui
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/chzyer/readline"
+	"github.com/logrhythm/promptui/screenbuf"
+)
+
+// Terminal is the terminal a prompt or a select runs in, replacing the terminal of the process. When it is set,
+// the Stdin and the Stdout of the prompt are used as the input and the output of the terminal, even if they are
+// not terminals themselves. TestTerminal implements it to run prompts in tests.
+type Terminal interface {
+	// Width returns the number of columns of the terminal.
+	Width() int
+
+	// Height returns the number of lines of the terminal.
+	Height() int
+
+	// MakeRaw switches the terminal to raw mode, where the keys are read one at a time without being echoed.
+	MakeRaw() error
+
+	// Restore switches the terminal back to the mode it was in before MakeRaw.
+	Restore() error
+}
+
+// configure makes readline use the terminal t instead of the terminal of the process.
+func configure(c *readline.Config, t Terminal) {
+	if t == nil {
+		return
+	}
+
+	c.FuncGetWidth = t.Width
+	c.FuncIsTerminal = func() bool { return true }
+	c.FuncMakeRaw = t.MakeRaw
+	c.FuncExitRaw = t.Restore
+	c.FuncOnWidthChanged = func(func()) {}
+}
+
+// bellFilter drops the bells readline rings on its own, as when the arrows reach the end of its history, which
+// prompts and selects do not use. The bell of the Bell options is written to the underlying writer instead.
+type bellFilter struct {
+	io.Writer
+}
+
+func (f bellFilter) Write(b []byte) (int, error) {
+	if string(b) == bell {
+		return len(b), nil
+	}
+	return f.Writer.Write(b)
+}
+
+// newScreenBuf creates the screen buffer of a prompt or a select writing to w, sized like the terminal t if it is
+// set.
+func newScreenBuf(w io.Writer, isSelect bool, t Terminal) *screenbuf.ScreenBuf {
+	sb := screenbuf.New(w, isSelect)
+	if t != nil {
+		sb.WidthFunc = func() (int, error) { return t.Width(), nil }
+		sb.HeightFunc = func() (int, error) { return t.Height(), nil }
+	}
+	return sb
+}
+
+// TestTerminal is a Terminal of a fixed size for tests, reading its input from a scripted sequence of keys and
+// recording its output. It is used as the Terminal, the Stdin and the Stdout of a prompt or a select:
+//
+//	term := promptui.NewTestTerminal("Jalapeno", "\r")
+//	prompt := promptui.Prompt{Label: "Pepper", Terminal: term, Stdin: term, Stdout: term}
+//	value, err := prompt.Run()
+//
+// Each string of keys is read at once, like the escape sequence of a key or a burst of typing. Once all the keys
+// are read, the input is at its end and the prompt returns ErrEOF if it is still running.
+type TestTerminal struct {
+	// Columns and Rows are the size of the terminal. They default to 80 columns and 24 rows.
+	Columns int
+	Rows    int
+
+	mu     sync.Mutex
+	keys   []string
+	output bytes.Buffer
+	raw    bool
+}
+
+// NewTestTerminal creates a test terminal whose input is the given keys, like "\r" for <Enter> or "\x1b[B" for
+// the down arrow.
+func NewTestTerminal(keys ...string) *TestTerminal {
+	return &TestTerminal{
+		Columns: screenbuf.DefaultWidth,
+		Rows:    screenbuf.DefaultHeight,
+		keys:    keys,
+	}
+}
+
+// Width returns the Columns of the terminal.
+func (t *TestTerminal) Width() int {
+	return t.Columns
+}
+
+// Height returns the Rows of the terminal.
+func (t *TestTerminal) Height() int {
+	return t.Rows
+}
+
+// MakeRaw records that the terminal is in raw mode.
+func (t *TestTerminal) MakeRaw() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.raw = true
+	return nil
+}
+
+// Restore records that the terminal is no longer in raw mode.
+func (t *TestTerminal) Restore() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.raw = false
+	return nil
+}
+
+// IsRaw reports whether the terminal is in raw mode, which it should not be once a prompt returned.
+func (t *TestTerminal) IsRaw() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.raw
+}
+
+// Read reads the next string of keys of the terminal, or what is left of it if it does not fit in b.
+func (t *TestTerminal) Read(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for len(t.keys) > 0 && t.keys[0] == "" {
+		t.keys = t.keys[1:]
+	}
+	if len(t.keys) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(b, t.keys[0])
+	t.keys[0] = t.keys[0][n:]
+	return n, nil
+}
+
+// Write records the output written to the terminal.
+func (t *TestTerminal) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.output.Write(b)
+}
+
+// Close does nothing, so the terminal can be used by several prompts.
+func (t *TestTerminal) Close() error {
+	return nil
+}
+
+// Output returns everything written to the terminal, including the ANSI escape codes.
+func (t *TestTerminal) Output() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.output.String()
+}
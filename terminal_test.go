@@ -0,0 +1,180 @@
+package promptui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTestTerminal(t *testing.T) {
+	t.Run("prompt", func(t *testing.T) {
+		term := NewTestTerminal("Jalapeno", "\r")
+		p := Prompt{Label: "Pepper", Terminal: term, Stdin: term, Stdout: term}
+
+		value, err := p.Run()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if value != "Jalapeno" {
+			t.Errorf("Expected %q, got %q", "Jalapeno", value)
+		}
+		if term.IsRaw() {
+			t.Error("Expected the terminal to be restored")
+		}
+	})
+
+	t.Run("select", func(t *testing.T) {
+		term := NewTestTerminal("\x1b[B", "\x1b[B", "\r")
+		s := Select{
+			Label:    "Pepper",
+			Items:    []string{"Bell", "Jalapeno", "Habanero"},
+			Terminal: term,
+			Stdin:    term,
+			Stdout:   term,
+		}
+
+		index, value, err := s.Run()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if index != 2 || value != "Habanero" {
+			t.Errorf("Expected %d %q, got %d %q", 2, "Habanero", index, value)
+		}
+	})
+
+	t.Run("end of input", func(t *testing.T) {
+		term := NewTestTerminal("Jala")
+		p := Prompt{Label: "Pepper", Terminal: term, Stdin: term, Stdout: term}
+
+		_, err := p.Run()
+		if err != ErrEOF {
+			t.Errorf("Expected %v, got %v", ErrEOF, err)
+		}
+	})
+}
+
+// emulate returns the lines displayed by a terminal after writing out to it, handling the cursor movements and
+// the clearing codes written by the screen buffer. The styles and the other codes are ignored.
+func emulate(out string) []string {
+	var screen [][]rune
+	row, col := 0, 0
+	line := func() []rune {
+		for len(screen) <= row {
+			screen = append(screen, nil)
+		}
+		return screen[row]
+	}
+
+	runes := []rune(out)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '\r':
+			col = 0
+		case '\n':
+			row, col = row+1, 0
+		case '\b':
+			if col > 0 {
+				col--
+			}
+		case '\x1b':
+			j := i + 1
+			if j < len(runes) && runes[j] == '[' {
+				j++
+			}
+			start := j
+			for j < len(runes) && (runes[j] >= '0' && runes[j] <= '9' || runes[j] == ';' || runes[j] == '?') {
+				j++
+			}
+			if j >= len(runes) {
+				break
+			}
+			n, err := strconv.Atoi(string(runes[start:j]))
+			if err != nil {
+				n = 1
+			}
+
+			switch runes[j] {
+			case 'A':
+				row -= n
+				if row < 0 {
+					row = 0
+				}
+			case 'B':
+				row += n
+			case 'K':
+				line()
+				screen[row] = nil
+			case 'J':
+				l := line()
+				if col < len(l) {
+					screen[row] = l[:col]
+				}
+				screen = screen[:row+1]
+			}
+			i = j
+		default:
+			l := line()
+			for len(l) <= col {
+				l = append(l, ' ')
+			}
+			l[col] = r
+			screen[row] = l
+			col++
+		}
+	}
+
+	lines := make([]string, len(screen))
+	for i, l := range screen {
+		lines[i] = strings.TrimRight(string(l), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// openReader reads the keys of a TestTerminal, then waits for the prompt or the select to close it, like a
+// terminal where no other key is pressed.
+type openReader struct {
+	*TestTerminal
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newOpenReader(term *TestTerminal) *openReader {
+	return &openReader{TestTerminal: term, closed: make(chan struct{})}
+}
+
+func (r *openReader) Read(b []byte) (int, error) {
+	n, err := r.TestTerminal.Read(b)
+	if err == io.EOF {
+		<-r.closed
+	}
+	return n, err
+}
+
+func (r *openReader) Close() error {
+	r.once.Do(func() { close(r.closed) })
+	return nil
+}
+
+// pausedReader reads the keys of a TestTerminal, calling done once they are all read, after the select or the
+// prompt handled them.
+type pausedReader struct {
+	*TestTerminal
+	done func()
+}
+
+func (r *pausedReader) Read(b []byte) (int, error) {
+	n, err := r.TestTerminal.Read(b)
+	if err == io.EOF && r.done != nil {
+		// the last keys are handled while their frame is drawn, so the frame is given time to be flushed.
+		time.Sleep(100 * time.Millisecond)
+		r.done()
+		r.done = nil
+	}
+	return n, err
+}